@@ -0,0 +1,72 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const ctxKeyApp ctxKey = "app"
+
+// App describes the application the command line interface belongs to.
+type App struct {
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Version   string `json:"version,omitempty" yaml:"version,omitempty"`
+	Commit    string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty" yaml:"build_date,omitempty"`
+	Homepage  string `json:"homepage,omitempty" yaml:"homepage,omitempty"`
+}
+
+// AppFromContext returns the application metadata from the context, if present.
+func AppFromContext(ctx context.Context) (App, bool) {
+	app, hasApp := ctx.Value(ctxKeyApp).(App)
+	return app, hasApp
+}
+
+// WithApp sets the application metadata to the root command name and version,
+// provides them to subcommands, and adds a version subcommand displaying them.
+func WithApp(cbf CommandBuilderFunc, opts ...AppOption) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		if _, hasApp := AppFromContext(ctx); hasApp {
+			return nil, nil, errors.New("application metadata are already defined")
+		}
+
+		var app App
+		for _, opt := range opts {
+			opt(&app)
+		}
+		ctx = context.WithValue(ctx, ctxKeyApp, app)
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build root command: %w", err)
+		}
+
+		app.applyToCommand(cmd)
+
+		version, _, err := VersionCommand(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build version command: %w", err)
+		}
+		cmd.AddCommand(version)
+
+		return cmd, ctx, nil
+	}
+}
+
+func (app App) applyToCommand(cmd *cobra.Command) {
+	if app.Name != "" {
+		// keep the usage following the name, like the declared arguments
+		if i := strings.Index(cmd.Use, " "); i >= 0 {
+			cmd.Use = app.Name + cmd.Use[i:]
+		} else {
+			cmd.Use = app.Name
+		}
+	}
+	if app.Version != "" {
+		cmd.Version = app.Version
+	}
+}
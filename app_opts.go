@@ -0,0 +1,29 @@
+package clix
+
+// AppOption defines the signature of an application metadata applier.
+type AppOption func(app *App)
+
+// AppWithName sets the application name, used as the root command name.
+func AppWithName(name string) AppOption {
+	return func(app *App) { app.Name = name }
+}
+
+// AppWithVersion sets the application version.
+func AppWithVersion(version string) AppOption {
+	return func(app *App) { app.Version = version }
+}
+
+// AppWithCommit sets the commit the application is built from.
+func AppWithCommit(commit string) AppOption {
+	return func(app *App) { app.Commit = commit }
+}
+
+// AppWithBuildDate sets the application build date.
+func AppWithBuildDate(date string) AppOption {
+	return func(app *App) { app.BuildDate = date }
+}
+
+// AppWithHomepage sets the application homepage.
+func AppWithHomepage(homepage string) AppOption {
+	return func(app *App) { app.Homepage = homepage }
+}
@@ -0,0 +1,28 @@
+package clix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AppOptions(t *testing.T) {
+	var app App
+	for _, opt := range []AppOption{
+		AppWithName("app"),
+		AppWithVersion("1.2.3"),
+		AppWithCommit("abcdef"),
+		AppWithBuildDate("2020-01-02"),
+		AppWithHomepage("https://example.com"),
+	} {
+		opt(&app)
+	}
+
+	assert.Equal(t, App{
+		Name:      "app",
+		Version:   "1.2.3",
+		Commit:    "abcdef",
+		BuildDate: "2020-01-02",
+		Homepage:  "https://example.com",
+	}, app)
+}
@@ -0,0 +1,84 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AppFromContext(t *testing.T) {
+	t.Run("with app in context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKeyApp, App{Name: "app"})
+		app, hasApp := AppFromContext(ctx)
+		assert.True(t, hasApp)
+		assert.Equal(t, App{Name: "app"}, app)
+	})
+	t.Run("without app in context", func(t *testing.T) {
+		_, hasApp := AppFromContext(context.Background())
+		assert.False(t, hasApp)
+	})
+}
+
+func Test_WithApp(t *testing.T) {
+	newCLI := func(handle HandlerFunc) *CLI {
+		return Command(WithApp(testCommand("cmd", nil),
+			AppWithName("app"),
+			AppWithVersion("1.2.3"),
+			AppWithCommit("abcdef"),
+			AppWithHomepage("https://example.com"),
+		)).SubCommand(testCommand("sub", handle))
+	}
+
+	t.Run("app is provided to subcommands", func(t *testing.T) {
+		var app App
+		require.NoError(t, newCLI(func(ctx context.Context, _, _ []string) error {
+			app, _ = AppFromContext(ctx)
+			return nil
+		}).Exec(context.Background(), []string{"sub"}))
+		assert.Equal(t, "app", app.Name)
+		assert.Equal(t, "abcdef", app.Commit)
+	})
+
+	t.Run("root command version flag", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, newCLI(nil).Exec(context.Background(), []string{"--version"}, ExecWithIO(IO{Out: &out})))
+		assert.Equal(t, "app version 1.2.3\n", out.String())
+	})
+
+	t.Run("version subcommand is added", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, newCLI(nil).Exec(context.Background(), []string{"version"}, ExecWithIO(IO{Out: &out})))
+		assert.Contains(t, out.String(), "Name:        app\n")
+	})
+
+	t.Run("app defined twice", func(t *testing.T) {
+		_, _, err := WithApp(WithApp(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{}, ctx, nil
+		}))(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		_, _, err := WithApp(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		})(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestApp_applyToCommand(t *testing.T) {
+	cmd := cobra.Command{Use: "cmd <arg> [opt]", Version: "dev"}
+
+	App{}.applyToCommand(&cmd)
+	assert.Equal(t, "cmd <arg> [opt]", cmd.Use)
+	assert.Equal(t, "dev", cmd.Version)
+
+	App{Name: "app", Version: "1.2.3"}.applyToCommand(&cmd)
+	assert.Equal(t, "app <arg> [opt]", cmd.Use)
+	assert.Equal(t, "1.2.3", cmd.Version)
+}
@@ -0,0 +1,282 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const annotationArgs = "clix_args"
+
+// ArgType defines the type a positional argument is converted to.
+type ArgType string
+
+// Types of positional arguments.
+const (
+	ArgTypeString   ArgType = "string"
+	ArgTypeInt      ArgType = "int"
+	ArgTypeDuration ArgType = "duration"
+	ArgTypeURL      ArgType = "url"
+	ArgTypeFile     ArgType = "file"
+	ArgTypeEnum     ArgType = "enum"
+)
+
+// Arg declares a positional argument of a command.
+type Arg struct {
+	Name string `json:"name"`
+	// Type defaults to ArgTypeString.
+	Type  ArgType `json:"type,omitempty"`
+	Usage string  `json:"usage,omitempty"`
+	// Required arguments can't be declared after optional ones.
+	Required bool `json:"required,omitempty"`
+	// Variadic argument consumes all remaining arguments,
+	// only the last declared argument can be variadic.
+	Variadic bool `json:"variadic,omitempty"`
+	// Enum lists the allowed values of ArgTypeEnum arguments.
+	Enum []string `json:"enum,omitempty"`
+}
+
+func (a Arg) useLine() string {
+	name := a.Name
+	if a.Variadic {
+		name += "..."
+	}
+	if a.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+func (a Arg) typeName() string {
+	switch {
+	case a.Type == "":
+		return string(ArgTypeString)
+	case a.Type == ArgTypeEnum:
+		return string(a.Type) + "(" + strings.Join(a.Enum, "|") + ")"
+	default:
+		return string(a.Type)
+	}
+}
+
+func (a Arg) convert(raw string) (interface{}, error) {
+	switch a.Type {
+	case ArgTypeString, "":
+		return raw, nil
+	case ArgTypeInt:
+		return strconv.Atoi(raw)
+	case ArgTypeDuration:
+		return time.ParseDuration(raw)
+	case ArgTypeURL:
+		u, err := url.Parse(raw)
+		if err == nil && (u.Scheme == "" || u.Host == "") {
+			err = errors.New("url must be absolute")
+		}
+		return u, err
+	case ArgTypeFile:
+		info, err := os.Stat(raw)
+		if err == nil && info.IsDir() {
+			err = errors.New("file is a directory")
+		}
+		return raw, err
+	case ArgTypeEnum:
+		for _, value := range a.Enum {
+			if raw == value {
+				return raw, nil
+			}
+		}
+		return nil, fmt.Errorf("value must be one of %s", strings.Join(a.Enum, ", "))
+	default:
+		return nil, fmt.Errorf("unknown argument type %q", a.Type)
+	}
+}
+
+// ArgValues maps the declared positional arguments names to their converted values.
+// Variadic arguments values are slices of the converted type.
+type ArgValues map[string]interface{}
+
+// String returns the value of a string, file, or enum argument.
+func (v ArgValues) String(name string) string {
+	s, _ := v[name].(string)
+	return s
+}
+
+// Strings returns the values of a variadic string, file, or enum argument.
+func (v ArgValues) Strings(name string) []string {
+	s, _ := v[name].([]string)
+	return s
+}
+
+// Int returns the value of an int argument.
+func (v ArgValues) Int(name string) int {
+	i, _ := v[name].(int)
+	return i
+}
+
+// Duration returns the value of a duration argument.
+func (v ArgValues) Duration(name string) time.Duration {
+	d, _ := v[name].(time.Duration)
+	return d
+}
+
+// URL returns the value of an url argument.
+func (v ArgValues) URL(name string) *url.URL {
+	u, _ := v[name].(*url.URL)
+	return u
+}
+
+// WithArgs declares the positional arguments of a command, which are validated
+// and converted before the handler is called. Converted values are available
+// through the invocation. The command use line and help are updated accordingly.
+func WithArgs(cbf CommandBuilderFunc, args ...Arg) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+
+		if err := validateArgsDeclaration(args); err != nil {
+			return nil, nil, fmt.Errorf("invalid arguments declaration: %w", err)
+		}
+
+		raw, err := json.Marshal(args)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to serialize arguments declaration: %w", err)
+		}
+		setAnnotation(cmd, annotationArgs, string(raw))
+
+		if name := cmd.Name(); name != "" {
+			useLine := []string{name}
+			for _, arg := range args {
+				useLine = append(useLine, arg.useLine())
+			}
+			cmd.Use = strings.Join(useLine, " ")
+		}
+
+		var help strings.Builder
+		tw := tabwriter.NewWriter(&help, 0, 0, 3, ' ', 0)
+		for _, arg := range args {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", arg.useLine(), arg.typeName(), arg.Usage)
+		}
+		tw.Flush() // nolint: errcheck, gosec
+		addHelpSection(cmd, "Arguments", strings.TrimRight(help.String(), " \n"))
+
+		return cmd, ctx, nil
+	}
+}
+
+func validateArgsDeclaration(args []Arg) error {
+	names := make(map[string]bool)
+	for i, arg := range args {
+		if arg.Name == "" {
+			return fmt.Errorf("argument %d has no name", i)
+		}
+		if names[arg.Name] {
+			return fmt.Errorf("argument %q is declared twice", arg.Name)
+		}
+		names[arg.Name] = true
+
+		if arg.Variadic && i != len(args)-1 {
+			return fmt.Errorf("variadic argument %q must be the last one", arg.Name)
+		}
+		if arg.Required && i > 0 && !args[i-1].Required {
+			return fmt.Errorf("required argument %q can't follow an optional one", arg.Name)
+		}
+		if arg.Type == ArgTypeEnum && len(arg.Enum) == 0 {
+			return fmt.Errorf("enum argument %q has no allowed values", arg.Name)
+		}
+	}
+	return nil
+}
+
+// argsFromCommand returns the positional arguments declared with WithArgs.
+func argsFromCommand(cmd *cobra.Command) ([]Arg, bool, error) {
+	raw, declared := cmd.Annotations[annotationArgs]
+	if !declared {
+		return nil, false, nil
+	}
+
+	var args []Arg
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return nil, true, fmt.Errorf("unable to deserialize arguments declaration: %w", err)
+	}
+	return args, true, nil
+}
+
+// parseArgs validates and converts the provided positional arguments.
+func parseArgs(declared []Arg, args []string) (ArgValues, error) {
+	values := make(ArgValues)
+
+	for i, arg := range declared {
+		if i >= len(args) {
+			if arg.Required {
+				return nil, &UsageError{Err: fmt.Errorf("missing required argument %q", arg.Name)}
+			}
+			continue
+		}
+
+		if !arg.Variadic {
+			value, err := arg.convert(args[i])
+			if err != nil {
+				return nil, &UsageError{Err: fmt.Errorf("invalid argument %q: %w", arg.Name, err)}
+			}
+			values[arg.Name] = value
+			continue
+		}
+
+		var converted []interface{}
+		for _, raw := range args[i:] {
+			value, err := arg.convert(raw)
+			if err != nil {
+				return nil, &UsageError{Err: fmt.Errorf("invalid argument %q: %w", arg.Name, err)}
+			}
+			converted = append(converted, value)
+		}
+		values[arg.Name] = typedSlice(arg.Type, converted)
+	}
+
+	if len(declared) == 0 || !declared[len(declared)-1].Variadic {
+		if len(args) > len(declared) {
+			return nil, &UsageError{Err: fmt.Errorf("accepts at most %d argument(s), received %d", len(declared), len(args))}
+		}
+	}
+
+	return values, nil
+}
+
+func typedSlice(typ ArgType, values []interface{}) interface{} {
+	switch typ {
+	case ArgTypeInt:
+		s := make([]int, len(values))
+		for i, v := range values {
+			s[i] = v.(int)
+		}
+		return s
+	case ArgTypeDuration:
+		s := make([]time.Duration, len(values))
+		for i, v := range values {
+			s[i] = v.(time.Duration)
+		}
+		return s
+	case ArgTypeURL:
+		s := make([]*url.URL, len(values))
+		for i, v := range values {
+			s[i] = v.(*url.URL)
+		}
+		return s
+	default:
+		s := make([]string, len(values))
+		for i, v := range values {
+			s[i] = v.(string)
+		}
+		return s
+	}
+}
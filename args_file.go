@@ -0,0 +1,137 @@
+package clix
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// validate makes sure the prefix can't be confused with a regular argument.
+func (o *argsFileOptions) validate() error {
+	if o.prefix == "" {
+		return errors.New("argument file prefix can't be empty")
+	}
+	return nil
+}
+
+// validateFlagFiles makes sure the prefix can't be confused with a flag value
+// read from a file, like the one of "--token @token.txt".
+func (o *argsFileOptions) validateFlagFiles(cmd *cobra.Command, withFlagFiles bool) error {
+	if !strings.HasPrefix(o.prefix, "@") {
+		return nil
+	}
+	if withFlagFiles {
+		return fmt.Errorf("argument file prefix %q collides with flag values read from files", o.prefix)
+	}
+	if hasSecretFlags(cmd) {
+		return fmt.Errorf("argument file prefix %q collides with secret flag values read from files", o.prefix)
+	}
+	return nil
+}
+
+// expandArgsFiles replaces the arguments starting with the prefix by the arguments
+// contained in the file they name, recursively. Arguments after a double dash are
+// not expanded.
+func expandArgsFiles(args []string, o *argsFileOptions) ([]string, error) {
+	expanded, _, err := expandArgsFilesAt(args, o, 0, false)
+	return expanded, err
+}
+
+func expandArgsFilesAt(args []string, o *argsFileOptions, depth int, dashed bool) ([]string, bool, error) {
+	expanded := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if dashed || arg == o.prefix || !strings.HasPrefix(arg, o.prefix) {
+			dashed = dashed || arg == "--"
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		path := strings.TrimPrefix(arg, o.prefix)
+		if depth >= o.maxDepth {
+			return nil, false, fmt.Errorf("unable to expand argument file %q: nested deeper than %d levels", path, o.maxDepth)
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to read argument file: %w", err)
+		}
+
+		fileArgs, err := splitArgsFile(string(content))
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to parse argument file %q: %w", path, err)
+		}
+
+		fileArgs, dashed, err = expandArgsFilesAt(fileArgs, o, depth+1, dashed)
+		if err != nil {
+			return nil, false, err
+		}
+		expanded = append(expanded, fileArgs...)
+	}
+
+	return expanded, dashed, nil
+}
+
+// splitArgsFile splits the content of an argument file into arguments, like a shell would:
+// arguments are separated by spaces or new lines, can be quoted with single or double
+// quotes, characters are escaped with a backslash, and comments start with #.
+func splitArgsFile(content string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+		comment bool
+	)
+
+	for _, r := range content {
+		switch {
+		case comment:
+			comment = r != '\n'
+		case escaped:
+			escaped = false
+			if quote == '"' && r != '"' && r != '\\' && r != '\n' {
+				current.WriteRune('\\')
+			}
+			if r != '\n' { // a backslash before a new line continues the line
+				current.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '#' && !inArg:
+			comment = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	switch {
+	case quote != 0:
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	case escaped:
+		return nil, fmt.Errorf("unterminated escape sequence")
+	case inArg:
+		args = append(args, current.String())
+	}
+	return args, nil
+}
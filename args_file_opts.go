@@ -0,0 +1,26 @@
+package clix
+
+type argsFileOptions struct {
+	prefix   string
+	maxDepth int
+}
+
+func defaultArgsFileOptions() *argsFileOptions {
+	return &argsFileOptions{prefix: "@", maxDepth: 8}
+}
+
+// ArgsFileOption defines the signature of an option applier.
+type ArgsFileOption func(o *argsFileOptions)
+
+// ArgsFileWithPrefix sets the prefix of arguments naming an argument file, "@" by default.
+// The prefix can't be empty. A prefix starting with "@" can't be used along with
+// ExecWithFlagFiles or Secret flags, which read values like "@value.txt" from files.
+func ArgsFileWithPrefix(prefix string) ArgsFileOption {
+	return func(o *argsFileOptions) { o.prefix = prefix }
+}
+
+// ArgsFileWithMaxDepth sets how deep argument files can reference
+// other argument files, 8 levels by default.
+func ArgsFileWithMaxDepth(maxDepth int) ArgsFileOption {
+	return func(o *argsFileOptions) { o.maxDepth = maxDepth }
+}
@@ -0,0 +1,16 @@
+package clix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ArgsFileOptions(t *testing.T) {
+	o := defaultArgsFileOptions()
+	assert.Equal(t, &argsFileOptions{prefix: "@", maxDepth: 8}, o)
+
+	ArgsFileWithPrefix("%")(o)
+	ArgsFileWithMaxDepth(2)(o)
+	assert.Equal(t, &argsFileOptions{prefix: "%", maxDepth: 2}, o)
+}
@@ -0,0 +1,172 @@
+package clix
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArgsFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func Test_ExecWithArgsFiles(t *testing.T) {
+	var inv Invocation
+	cli := Command(testInvocationCommand("app", recordInvocation(&inv), func(cmd *cobra.Command) error {
+		cmd.Flags().String("region", "", "")
+		return nil
+	}))
+
+	dir := t.TempDir()
+	path := writeArgsFile(t, dir, "args.txt", "# deployment\n--region 'eu west'\nfirst -- @dashed\n")
+
+	require.NoError(t, cli.Exec(context.Background(), []string{"@" + path, "last"}, ExecWithArgsFiles()))
+	assert.Equal(t, []string{"first"}, inv.Args)
+	assert.Equal(t, []string{"@dashed", "last"}, inv.DashedArgs)
+	assert.Equal(t, map[string]string{"region": "eu west"}, inv.ChangedFlags)
+	assert.Equal(t, []string{"--region", "eu west", "first", "--", "@dashed", "last"}, inv.RawArgs)
+
+	err := cli.Exec(context.Background(), []string{"@" + filepath.Join(dir, "missing.txt")},
+		ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}), ExecWithArgsFiles(),
+	)
+	assert.Error(t, err)
+
+	require.NoError(t, cli.Exec(context.Background(), []string{"@" + path}))
+	assert.Equal(t, []string{"@" + path}, inv.Args)
+
+	err = cli.Exec(context.Background(), []string{"@" + path},
+		ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}), ExecWithArgsFiles(ArgsFileWithPrefix("")),
+	)
+	assert.EqualError(t, err, "invalid argument files options: argument file prefix can't be empty")
+
+	require.NoError(t, cli.Exec(context.Background(), []string{"+" + path},
+		ExecWithArgsFiles(ArgsFileWithPrefix("+")), ExecWithFlagFiles(),
+	))
+	assert.Equal(t, []string{"first"}, inv.Args)
+
+	err = cli.Exec(context.Background(), []string{"@" + path},
+		ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}), ExecWithArgsFiles(), ExecWithFlagFiles(),
+	)
+	assert.EqualError(t, err, `invalid argument files options: argument file prefix "@" collides with flag values read from files`)
+}
+
+func Test_argsFileOptions_validateFlagFiles(t *testing.T) {
+	newCommand := func(withSecret bool) *cobra.Command {
+		var token Secret
+		cmd, sub := &cobra.Command{Use: "app"}, &cobra.Command{Use: "deploy"}
+		if withSecret {
+			SecretVar(sub.Flags(), &token, "token", "", "")
+		}
+		cmd.AddCommand(sub)
+		return cmd
+	}
+
+	assert.NoError(t, defaultArgsFileOptions().validateFlagFiles(newCommand(false), false))
+	assert.NoError(t, (&argsFileOptions{prefix: "+"}).validateFlagFiles(newCommand(true), true))
+	assert.EqualError(t, (&argsFileOptions{prefix: "@args:"}).validateFlagFiles(newCommand(false), true),
+		`argument file prefix "@args:" collides with flag values read from files`)
+	assert.EqualError(t, defaultArgsFileOptions().validateFlagFiles(newCommand(true), false),
+		`argument file prefix "@" collides with secret flag values read from files`)
+}
+
+func Test_argsFileOptions_validate(t *testing.T) {
+	assert.NoError(t, defaultArgsFileOptions().validate())
+	assert.EqualError(t, (&argsFileOptions{}).validate(), "argument file prefix can't be empty")
+}
+
+func Test_expandArgsFiles(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) }) // nolint: errcheck, gosec
+
+	writeArgsFile(t, dir, "nested.txt", "b @leaf.txt")
+	writeArgsFile(t, dir, "leaf.txt", "c -- @nested.txt")
+	writeArgsFile(t, dir, "loop.txt", "@loop.txt")
+
+	o := defaultArgsFileOptions()
+
+	t.Run("files are expanded recursively", func(t *testing.T) {
+		args, err := expandArgsFiles([]string{"a", "@nested.txt", "d", "@"}, o)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c", "--", "@nested.txt", "d", "@"}, args)
+	})
+
+	t.Run("arguments after a double dash are not expanded", func(t *testing.T) {
+		args, err := expandArgsFiles([]string{"--", "@nested.txt"}, o)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"--", "@nested.txt"}, args)
+	})
+
+	t.Run("recursion is limited", func(t *testing.T) {
+		_, err := expandArgsFiles([]string{"@loop.txt"}, o)
+		assert.EqualError(t, err, `unable to expand argument file "loop.txt": nested deeper than 8 levels`)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		writeArgsFile(t, dir, "invalid.txt", `"unterminated`)
+		_, err := expandArgsFiles([]string{"@invalid.txt"}, o)
+		assert.EqualError(t, err, `unable to parse argument file "invalid.txt": unterminated " quote`)
+	})
+}
+
+func Test_splitArgsFile(t *testing.T) {
+	for name, test := range map[string]struct {
+		content     string
+		expected    []string
+		expectedErr string
+	}{
+		"empty": {
+			content: "  \n\t\n",
+		},
+		"spaces and new lines": {
+			content:  "--flag value\n  arg\targ2\r\n",
+			expected: []string{"--flag", "value", "arg", "arg2"},
+		},
+		"comments": {
+			content:  "# comment\narg # trailing comment\nnot#comment",
+			expected: []string{"arg", "not#comment"},
+		},
+		"single quotes": {
+			content:  `'a b' 'c\d' '' x'y'z`,
+			expected: []string{"a b", `c\d`, "", "xyz"},
+		},
+		"double quotes": {
+			content:  `"a 'b'" "c\"d" "e\\f" "g\h" "# not a comment"`,
+			expected: []string{"a 'b'", `c"d`, `e\f`, `g\h`, "# not a comment"},
+		},
+		"escapes": {
+			content:  "a\\ b \\#c d\\\ne",
+			expected: []string{"a b", "#c", "de"},
+		},
+		"unterminated quote": {
+			content:     `'a`,
+			expectedErr: "unterminated ' quote",
+		},
+		"unterminated escape": {
+			content:     `a\`,
+			expectedErr: "unterminated escape sequence",
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			args, err := splitArgsFile(test.content)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, args)
+		})
+	}
+}
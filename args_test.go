@@ -0,0 +1,195 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArg_convert(t *testing.T) {
+	file, err := ioutil.TempFile("", "clix-args")
+	require.NoError(t, err)
+	defer os.Remove(file.Name()) // nolint: errcheck, gosec
+	require.NoError(t, file.Close())
+
+	for name, test := range map[string]struct {
+		arg         Arg
+		raw         string
+		expected    interface{}
+		expectedErr bool
+	}{
+		"default string":    {arg: Arg{}, raw: "a", expected: "a"},
+		"int":               {arg: Arg{Type: ArgTypeInt}, raw: "42", expected: 42},
+		"invalid int":       {arg: Arg{Type: ArgTypeInt}, raw: "a", expectedErr: true},
+		"duration":          {arg: Arg{Type: ArgTypeDuration}, raw: "1m", expected: time.Minute},
+		"invalid duration":  {arg: Arg{Type: ArgTypeDuration}, raw: "1", expectedErr: true},
+		"url":               {arg: Arg{Type: ArgTypeURL}, raw: "https://a.b/c", expected: &url.URL{Scheme: "https", Host: "a.b", Path: "/c"}},
+		"relative url":      {arg: Arg{Type: ArgTypeURL}, raw: "/c", expectedErr: true},
+		"file":              {arg: Arg{Type: ArgTypeFile}, raw: file.Name(), expected: file.Name()},
+		"file is directory": {arg: Arg{Type: ArgTypeFile}, raw: os.TempDir(), expectedErr: true},
+		"file not found":    {arg: Arg{Type: ArgTypeFile}, raw: file.Name() + "-404", expectedErr: true},
+		"enum":              {arg: Arg{Type: ArgTypeEnum, Enum: []string{"a", "b"}}, raw: "b", expected: "b"},
+		"invalid enum":      {arg: Arg{Type: ArgTypeEnum, Enum: []string{"a", "b"}}, raw: "c", expectedErr: true},
+		"unknown type":      {arg: Arg{Type: "boum"}, raw: "a", expectedErr: true},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			value, err := test.arg.convert(test.raw)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestArgValues(t *testing.T) {
+	u := &url.URL{Host: "a"}
+	values := ArgValues{
+		"string":   "a",
+		"strings":  []string{"a", "b"},
+		"int":      1,
+		"duration": time.Second,
+		"url":      u,
+	}
+
+	assert.Equal(t, "a", values.String("string"))
+	assert.Equal(t, []string{"a", "b"}, values.Strings("strings"))
+	assert.Equal(t, 1, values.Int("int"))
+	assert.Equal(t, time.Second, values.Duration("duration"))
+	assert.Equal(t, u, values.URL("url"))
+
+	assert.Empty(t, values.String("int"))
+	assert.Nil(t, values.URL("missing"))
+}
+
+func Test_validateArgsDeclaration(t *testing.T) {
+	assert.NoError(t, validateArgsDeclaration([]Arg{
+		{Name: "a", Required: true},
+		{Name: "b"},
+		{Name: "c", Variadic: true},
+	}))
+	assert.Error(t, validateArgsDeclaration([]Arg{{}}))
+	assert.Error(t, validateArgsDeclaration([]Arg{{Name: "a"}, {Name: "a"}}))
+	assert.Error(t, validateArgsDeclaration([]Arg{{Name: "a", Variadic: true}, {Name: "b"}}))
+	assert.Error(t, validateArgsDeclaration([]Arg{{Name: "a"}, {Name: "b", Required: true}}))
+	assert.Error(t, validateArgsDeclaration([]Arg{{Name: "a", Type: ArgTypeEnum}}))
+}
+
+func Test_parseArgs(t *testing.T) {
+	declared := []Arg{
+		{Name: "count", Type: ArgTypeInt, Required: true},
+		{Name: "durations", Type: ArgTypeDuration, Variadic: true},
+	}
+
+	t.Run("all arguments are converted", func(t *testing.T) {
+		values, err := parseArgs(declared, []string{"1", "1s", "2s"})
+		require.NoError(t, err)
+		assert.Equal(t, ArgValues{
+			"count":     1,
+			"durations": []time.Duration{time.Second, 2 * time.Second},
+		}, values)
+	})
+
+	t.Run("optional arguments can be omitted", func(t *testing.T) {
+		values, err := parseArgs(declared, []string{"1"})
+		require.NoError(t, err)
+		assert.Equal(t, ArgValues{"count": 1}, values)
+	})
+
+	for name, test := range map[string]struct {
+		declared []Arg
+		args     []string
+	}{
+		"missing required argument":   {declared: declared},
+		"invalid argument":            {declared: declared, args: []string{"a"}},
+		"invalid variadic argument":   {declared: declared, args: []string{"1", "1s", "a"}},
+		"too many arguments provided": {declared: declared[:1], args: []string{"1", "2"}},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := parseArgs(test.declared, test.args)
+			var usageErr *UsageError
+			assert.True(t, errors.As(err, &usageErr))
+		})
+	}
+}
+
+func Test_WithArgs(t *testing.T) {
+	newCLI := func(handle InvocationHandlerFunc) *CLI {
+		return Command(WithArgs(testInvocationCommand("app", handle, silenceErrors),
+			Arg{Name: "env", Type: ArgTypeEnum, Enum: []string{"dev", "prod"}, Required: true, Usage: "target environment"},
+			Arg{Name: "retries", Type: ArgTypeInt, Variadic: true},
+		))
+	}
+
+	t.Run("arguments are converted", func(t *testing.T) {
+		called := false
+		err := newCLI(func(_ context.Context, inv Invocation) error {
+			assert.Equal(t, ArgValues{"env": "prod", "retries": []int{1, 2}}, inv.Values)
+			called = true
+			return nil
+		}).Exec(context.Background(), []string{"prod", "1", "2"})
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("invalid arguments are reported with usage", func(t *testing.T) {
+		var out bytes.Buffer
+		err := newCLI(func(context.Context, Invocation) error {
+			t.Fatal("should not be called")
+			return nil
+		}).
+			Exec(context.Background(), []string{"staging"}, ExecWithIO(IO{Out: &out, Err: &out}))
+		var usageErr *UsageError
+		require.True(t, errors.As(err, &usageErr))
+		assert.Contains(t, out.String(), "Usage:")
+	})
+
+	t.Run("use line and help are generated", func(t *testing.T) {
+		cmd, _, err := newCLI(nil).Build()(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "app <env> [retries...]", cmd.Use)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		require.NoError(t, cmd.Usage())
+		assert.Contains(t, out.String(), "Arguments:\n"+
+			"  <env>          enum(dev|prod)   target environment\n"+
+			"  [retries...]   int\n")
+	})
+
+	t.Run("invalid declaration", func(t *testing.T) {
+		_, _, err := Command(WithArgs(testCommand("app", nil), Arg{})).Build()(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		_, _, err := Command(WithArgs(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		})).Build()(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func Test_argsFromCommand(t *testing.T) {
+	args, declared, err := argsFromCommand(&cobra.Command{})
+	require.NoError(t, err)
+	assert.False(t, declared)
+	assert.Nil(t, args)
+
+	_, declared, err = argsFromCommand(&cobra.Command{Annotations: map[string]string{annotationArgs: "{"}})
+	assert.Error(t, err)
+	assert.True(t, declared)
+}
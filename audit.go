@@ -0,0 +1,186 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// Outcomes of an audited invocation.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomePanic   = "panic"
+)
+
+// AuditRecord describes an invocation of a command, once handled.
+type AuditRecord struct {
+	Time     time.Time         `json:"time"`
+	User     string            `json:"user,omitempty"`
+	Host     string            `json:"host,omitempty"`
+	Command  string            `json:"command"`
+	Args     []string          `json:"args,omitempty"`
+	Flags    map[string]string `json:"flags,omitempty"`
+	Duration time.Duration     `json:"duration_ns"`
+	Outcome  string            `json:"outcome"`
+	Error    string            `json:"error,omitempty"`
+	ExitCode int               `json:"exit_code"`
+}
+
+// AuditSink stores audit records.
+type AuditSink interface {
+	WriteAuditRecord(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc implements AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+// WriteAuditRecord implements AuditSink.
+func (f AuditSinkFunc) WriteAuditRecord(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// NewAuditWriterSink creates a sink writing records as JSON lines to the provided writer,
+// like a log/syslog writer. Each record is written with a single call to Write.
+func NewAuditWriterSink(w io.Writer) AuditSink {
+	var m sync.Mutex
+	return AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+		raw, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("unable to serialize audit record: %w", err)
+		}
+
+		m.Lock()
+		defer m.Unlock()
+		if _, err := w.Write(append(raw, '\n')); err != nil {
+			return fmt.Errorf("unable to write audit record: %w", err)
+		}
+		return nil
+	})
+}
+
+// NewAuditFileSink creates a sink appending records as JSON lines to the provided file,
+// created if it does not exist.
+func NewAuditFileSink(path string) AuditSink {
+	return AuditSinkFunc(func(ctx context.Context, record AuditRecord) error {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("unable to open/create audit file %q: %w", path, err)
+		}
+
+		if err := NewAuditWriterSink(file).WriteAuditRecord(ctx, record); err != nil {
+			file.Close() // nolint: errcheck, gosec
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("unable to close audit file %q: %w", path, err)
+		}
+		return nil
+	})
+}
+
+const ctxKeyAudit ctxKey = "audit"
+
+type auditor struct {
+	sink AuditSink
+	o    *auditOptions
+}
+
+// WithAudit writes a record of each invocation of the command, and all its subcommands,
+// to the sink, sensitive flags values masked. Invocations are recorded once handled,
+// including the ones failing before reaching the handler, like a refused destructive
+// command, and with the exit code of the timeout. When the record can't be written,
+// the error is logged, and returned if the invocation succeeded.
+func (cli *CLI) WithAudit(sink AuditSink, opts ...AuditOption) *CLI {
+	o := defaultAuditOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cli.auditor = &auditor{sink: sink, o: o}
+	return cli
+}
+
+func withAuditor(ctx context.Context, a *auditor) context.Context {
+	if a == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKeyAudit, a)
+}
+
+// auditInvocation executes the invocation, and records it if the command is audited.
+func auditInvocation(ctx context.Context, inv *Invocation, exec func() error) error {
+	a, isAudited := ctx.Value(ctxKeyAudit).(*auditor)
+	if !isAudited {
+		return exec()
+	}
+
+	start := a.o.now()
+	err := exec()
+
+	if auditErr := a.write(ctx, *inv, start, err); auditErr != nil {
+		if log := LoggerFromContext(ctx); log != nil {
+			log.WithError(auditErr).Error("unable to write audit record")
+		}
+		if err == nil {
+			err = auditErr
+		}
+	}
+	return err
+}
+
+func (a *auditor) write(ctx context.Context, inv Invocation, start time.Time, err error) error {
+	record := AuditRecord{
+		Time:     start.UTC(),
+		User:     a.o.user(),
+		Host:     a.o.host(),
+		Command:  inv.CommandPath,
+		Args:     inv.Args,
+		Flags:    make(map[string]string, len(inv.ChangedFlags)),
+		Duration: a.o.now().Sub(start),
+		Outcome:  AuditOutcomeSuccess,
+		ExitCode: ExitCode(err),
+	}
+
+	if err != nil {
+		record.Outcome = AuditOutcomeFailure
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			record.Outcome = AuditOutcomePanic
+		}
+		record.Error = err.Error()
+	}
+
+	for name, value := range inv.ChangedFlags {
+		if isSensitiveFlag(lookupFlag(inv.flags, "--"+name)) {
+			value = maskedValue
+		}
+		record.Flags[name] = value
+	}
+
+	if err := a.sink.WriteAuditRecord(ctx, record); err != nil {
+		return fmt.Errorf("unable to audit invocation: %w", err)
+	}
+	return nil
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+func currentHost() string {
+	host, _ := os.Hostname()
+	return host
+}
@@ -0,0 +1,40 @@
+package clix
+
+import (
+	"time"
+)
+
+type auditOptions struct {
+	user func() string
+	host func() string
+	now  func() time.Time
+}
+
+func defaultAuditOptions() *auditOptions {
+	return &auditOptions{
+		user: currentUser,
+		host: currentHost,
+		now:  time.Now,
+	}
+}
+
+// AuditOption defines the signature of an option applier.
+type AuditOption func(o *auditOptions)
+
+// AuditWithUser sets the function returning the user recorded,
+// the current system user by default.
+func AuditWithUser(user func() string) AuditOption {
+	return func(o *auditOptions) { o.user = user }
+}
+
+// AuditWithHost sets the function returning the host recorded,
+// the hostname by default.
+func AuditWithHost(host func() string) AuditOption {
+	return func(o *auditOptions) { o.host = host }
+}
+
+// AuditWithClock sets the function returning the current time,
+// used to timestamp records and measure durations.
+func AuditWithClock(now func() time.Time) AuditOption {
+	return func(o *auditOptions) { o.now = now }
+}
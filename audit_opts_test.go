@@ -0,0 +1,24 @@
+package clix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AuditOptions(t *testing.T) {
+	o := defaultAuditOptions()
+	assert.NotNil(t, o.user)
+	assert.NotNil(t, o.host)
+	assert.NotNil(t, o.now)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	AuditWithUser(func() string { return "alice" })(o)
+	AuditWithHost(func() string { return "bastion" })(o)
+	AuditWithClock(func() time.Time { return now })(o)
+
+	assert.Equal(t, "alice", o.user())
+	assert.Equal(t, "bastion", o.host())
+	assert.Equal(t, now, o.now())
+}
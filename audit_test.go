@@ -0,0 +1,215 @@
+package clix
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CLI_WithAudit(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	newCLI := func(sink AuditSink, handle HandlerFunc, decorators ...func(CommandBuilderFunc) CommandBuilderFunc) *CLI {
+		deploy := testCommand("deploy", handle, func(cmd *cobra.Command) error {
+			SecretVar(cmd.Flags(), new(Secret), "token", "", "")
+			cmd.Flags().String("region", "", "")
+			return nil
+		})
+		for _, decorate := range decorators {
+			deploy = decorate(deploy)
+		}
+
+		return Command(testCommand("admin", nil)).SubCommand(deploy).WithAudit(sink,
+			AuditWithUser(func() string { return "alice" }),
+			AuditWithHost(func() string { return "bastion" }),
+			AuditWithClock(clock),
+		)
+	}
+
+	t.Run("successful invocations are recorded", func(t *testing.T) {
+		var records []AuditRecord
+		sink := AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		})
+
+		require.NoError(t, newCLI(sink, noopHandler).Exec(
+			context.Background(), []string{"deploy", "api", "--region", "eu", "--token", "s3cr3t"},
+		))
+		require.Len(t, records, 1)
+		assert.Equal(t, AuditRecord{
+			Time:     time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC),
+			User:     "alice",
+			Host:     "bastion",
+			Command:  "admin deploy",
+			Args:     []string{"api"},
+			Flags:    map[string]string{"region": "eu", "token": "****"},
+			Duration: time.Second,
+			Outcome:  AuditOutcomeSuccess,
+			ExitCode: ExitCodeSuccess,
+		}, records[0])
+	})
+
+	t.Run("failures and panics are recorded", func(t *testing.T) {
+		var records []AuditRecord
+		sink := AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		})
+		stdio := ExecWithIO(IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)})
+
+		err := newCLI(sink, func(context.Context, []string, []string) error {
+			return errors.New("boum")
+		}).Exec(context.Background(), []string{"deploy"}, stdio)
+		require.EqualError(t, err, "boum")
+
+		err = newCLI(sink, func(context.Context, []string, []string) error {
+			panic("boum")
+		}).Exec(context.Background(), []string{"deploy"}, stdio)
+		var panicErr *PanicError
+		require.True(t, errors.As(err, &panicErr))
+		assert.Contains(t, string(panicErr.Stack), "audit_test.go")
+
+		require.Len(t, records, 2)
+		assert.Equal(t, AuditOutcomeFailure, records[0].Outcome)
+		assert.Equal(t, "boum", records[0].Error)
+		assert.Equal(t, ExitCodeFailure, records[0].ExitCode)
+		assert.Equal(t, AuditOutcomePanic, records[1].Outcome)
+		assert.Equal(t, "handler panicked: boum", records[1].Error)
+		assert.Equal(t, ExitCodePanic, records[1].ExitCode)
+	})
+
+	t.Run("timeouts are recorded", func(t *testing.T) {
+		var records []AuditRecord
+		sink := AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		})
+
+		err := newCLI(sink, func(ctx context.Context, _, _ []string) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, func(cbf CommandBuilderFunc) CommandBuilderFunc {
+			return WithTimeout(cbf, time.Millisecond)
+		}).Exec(context.Background(), []string{"deploy"}, ExecWithIO(IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)}))
+		require.Equal(t, ExitCodeTimeout, ExitCode(err))
+
+		require.Len(t, records, 1)
+		assert.Equal(t, AuditOutcomeFailure, records[0].Outcome)
+		assert.Equal(t, ExitCodeTimeout, records[0].ExitCode)
+	})
+
+	t.Run("invocations failing before the handler are recorded", func(t *testing.T) {
+		var records []AuditRecord
+		sink := AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		})
+		stdio := ExecWithIO(IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)})
+		unreachable := HandlerFunc(func(context.Context, []string, []string) error {
+			t.Fatal("handler should not be called")
+			return nil
+		})
+
+		err := newCLI(sink, unreachable, func(cbf CommandBuilderFunc) CommandBuilderFunc {
+			return WithDestructive(cbf, destructiveWithInteractive(false))
+		}).Exec(context.Background(), []string{"deploy"}, stdio)
+		require.True(t, errors.Is(err, ErrConfirmationRequired))
+
+		err = newCLI(sink, unreachable, func(cbf CommandBuilderFunc) CommandBuilderFunc {
+			return WithFlagConstraints(cbf, FlagsRequired("region"))
+		}).Exec(context.Background(), []string{"deploy", "--token", "s3cr3t"}, stdio)
+		require.Error(t, err)
+
+		require.Len(t, records, 2)
+		assert.Equal(t, AuditOutcomeFailure, records[0].Outcome)
+		assert.Equal(t, "destructive command requires a confirmation, use --yes to confirm", records[0].Error)
+		assert.Equal(t, AuditOutcomeFailure, records[1].Outcome)
+		assert.Equal(t, map[string]string{"token": "****"}, records[1].Flags)
+	})
+
+	t.Run("sink failures are returned", func(t *testing.T) {
+		sink := AuditSinkFunc(func(context.Context, AuditRecord) error { return errors.New("disk full") })
+		stdio := ExecWithIO(IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)})
+
+		err := newCLI(sink, noopHandler).Exec(context.Background(), []string{"deploy"}, stdio)
+		assert.EqualError(t, err, "unable to audit invocation: disk full")
+
+		err = newCLI(sink, func(context.Context, []string, []string) error {
+			return errors.New("boum")
+		}).Exec(context.Background(), []string{"deploy"}, stdio)
+		assert.EqualError(t, err, "boum")
+	})
+
+	t.Run("invocations of nested command trees are recorded", func(t *testing.T) {
+		var records []AuditRecord
+		sink := AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		})
+
+		nested := Command(testCommand("cluster", nil)).SubCommand(testCommand("resize", noopHandler))
+
+		require.NoError(t, newCLI(sink, noopHandler).
+			SubCommand(nested.Build()).
+			Exec(context.Background(), []string{"cluster", "resize"}))
+		require.Len(t, records, 1)
+		assert.Equal(t, "admin cluster resize", records[0].Command)
+	})
+}
+
+func Test_NewAuditWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewAuditWriterSink(&buf)
+
+	require.NoError(t, sink.WriteAuditRecord(context.Background(), AuditRecord{Command: "app a", Outcome: AuditOutcomeSuccess}))
+	require.NoError(t, sink.WriteAuditRecord(context.Background(), AuditRecord{Command: "app b", Outcome: AuditOutcomeFailure, ExitCode: 1}))
+
+	assert.Equal(t, ""+
+		`{"time":"0001-01-01T00:00:00Z","command":"app a","duration_ns":0,"outcome":"success","exit_code":0}`+"\n"+
+		`{"time":"0001-01-01T00:00:00Z","command":"app b","duration_ns":0,"outcome":"failure","exit_code":1}`+"\n",
+		buf.String(),
+	)
+}
+
+func Test_NewAuditFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink := NewAuditFileSink(path)
+
+	for _, command := range []string{"app a", "app b"} {
+		require.NoError(t, sink.WriteAuditRecord(context.Background(), AuditRecord{Command: command}))
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close() // nolint: errcheck, gosec
+
+	var commands []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		commands = append(commands, record.Command)
+	}
+	assert.Equal(t, []string{"app a", "app b"}, commands)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	assert.Error(t, NewAuditFileSink(filepath.Join(t.TempDir(), "missing", "audit.jsonl")).WriteAuditRecord(context.Background(), AuditRecord{}))
+}
@@ -4,6 +4,7 @@ package clix
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 )
@@ -12,6 +13,8 @@ import (
 type CLI struct {
 	command     CommandBuilderFunc
 	subcommands []CommandBuilderFunc
+	middlewares []Middleware
+	auditor     *auditor
 }
 
 // CommandBuilderFunc defines a cobra command builder func.
@@ -27,8 +30,16 @@ func (cli *CLI) SubCommand(cmd CommandBuilderFunc) *CLI {
 }
 
 // Build return a concatenated command builder that adds all subcommands to the root command.
+// Resources opened while executing the built command, like log files, are closed
+// with CloseResources called with the returned context.
 func (cli *CLI) Build() CommandBuilderFunc {
 	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		if _, hasClosers := ctx.Value(ctxKeyClosers).(*[]io.Closer); !hasClosers {
+			ctx = context.WithValue(ctx, ctxKeyClosers, new([]io.Closer))
+		}
+		ctx = withMiddlewares(ctx, cli.middlewares)
+		ctx = withAuditor(ctx, cli.auditor)
+
 		command, ctx, err := cli.command(ctx)
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to build command: %w", err)
@@ -40,18 +51,92 @@ func (cli *CLI) Build() CommandBuilderFunc {
 			}
 			command.AddCommand(sub)
 		}
+		withHelpSections(command)
 		return command, ctx, nil
 	}
 }
 
-// Exec executes the command given by args.
-func (cli *CLI) Exec(ctx context.Context, args []string) error {
-	cmd, _, err := cli.Build()(ctx)
+// Exec executes the command given by args. Flags bound to an environment variable
+// with BindFlagEnv, and not provided in args, are set from the environment.
+func (cli *CLI) Exec(ctx context.Context, args []string, opts ...ExecOption) (err error) {
+	var o execOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.argsFiles != nil {
+		if err := o.argsFiles.validate(); err != nil {
+			return fmt.Errorf("invalid argument files options: %w", err)
+		}
+	}
+
+	// raw args are set once argument files are expanded,
+	// which requires the command to know its flags
+	rawArgs := args
+	ctx = context.WithValue(ctx, ctxKeyRawArgs, &rawArgs)
+
+	cmd, ctx, err := cli.Build()(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to build command: %w", err)
 	}
+	defer func() {
+		if closeErr := CloseResources(ctx); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if o.argsFiles != nil {
+		if err := o.argsFiles.validateFlagFiles(cmd, o.flagFiles != nil); err != nil {
+			return fmt.Errorf("invalid argument files options: %w", err)
+		}
+		if args, err = expandArgsFiles(args, o.argsFiles); err != nil {
+			return fmt.Errorf("unable to expand arguments: %w", err)
+		}
+		rawArgs = args
+	}
+	o.io.applyToCommand(cmd)
+	maxValueSize := int64(defaultMaxValueSize)
+	if o.flagFiles != nil {
+		wrapFileFlagValues(cmd, o.flagFiles)
+		maxValueSize = o.flagFiles.maxSize
+	}
+	bindSecretFlags(cmd, maxValueSize)
+	applyFlagsEnv(cmd)
 	cmd.SetArgs(args)
-	return cmd.Execute()
+
+	_, end := startStep(ctx, StepExec)
+	err = cmd.Execute()
+	end(err)
+	return err
+}
+
+const ctxKeyClosers ctxKey = "closers"
+
+// closeAfterExec registers a resource, opened while executing the command,
+// to close with CloseResources, called once Exec returns.
+func closeAfterExec(ctx context.Context, closer io.Closer) {
+	if closers, isBuilt := ctx.Value(ctxKeyClosers).(*[]io.Closer); isBuilt {
+		*closers = append(*closers, closer)
+	}
+}
+
+// CloseResources closes the resources opened while executing a command built
+// with Build, like log files, in the reverse order they were opened. The context is
+// the one returned by Build. It is called by Exec, and is safe to call more than once.
+func CloseResources(ctx context.Context) error {
+	closers, isBuilt := ctx.Value(ctxKeyClosers).(*[]io.Closer)
+	if !isBuilt {
+		return nil
+	}
+
+	var err error
+	for i := len(*closers) - 1; i >= 0; i-- {
+		if closeErr := (*closers)[i].Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("unable to close resource: %w", closeErr)
+		}
+	}
+	*closers = nil
+	return err
 }
 
 type (
@@ -70,32 +155,68 @@ func (h HandlerFunc) Handle(ctx context.Context, args []string, dashedArgs []str
 	return h(ctx, args, dashedArgs)
 }
 
-// ExecHandler execs the provided handler function.
+// ExecHandler execs the provided handler function, wrapped by the middlewares attached with CLI.Use.
+// The command streams are available in the handler's context through IOFromContext.
 func ExecHandler(ctx context.Context, getHandler GetHandlerFunc) func(*cobra.Command, []string) error {
-	return func(c *cobra.Command, args []string) error {
-		// before reaching this point, we want the usage but after that, if we
-		// have an error, we do want to handle when to display it, and when not
-		c.SilenceUsage = true
-
-		cmd, err := getHandler(func() {
-			c.Help() // nolint: errcheck, gosec
-		})
+	return ExecInvocationHandler(ctx, func(help func()) (InvocationHandler, error) {
+		handler, err := getHandler(help)
 		if err != nil {
+			return nil, err
+		}
+		return AdaptHandler(handler), nil
+	})
+}
+
+// appendPersistentPreRunE adds a pre run function to the command's persistent
+// pre run, keeping the previously defined one, if any, to be executed first.
+func appendPersistentPreRunE(cmd *cobra.Command, preRun func(*cobra.Command, []string) error) {
+	previous := takePersistentPreRunE(cmd)
+	if previous == nil {
+		cmd.PersistentPreRunE = preRun
+		return
+	}
+
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if err := previous(c, args); err != nil {
 			return err
 		}
+		return preRun(c, args)
+	}
+}
 
-		if len(args) == 0 {
-			return cmd.Handle(ctx, nil, nil)
+// PrependPersistentPreRunE adds a pre run function to the command's persistent
+// pre run, keeping the previously defined one, if any, to be executed after.
+// Decorators use it to initialize dependencies before the ones of decorators applied before.
+func PrependPersistentPreRunE(cmd *cobra.Command, preRun func(*cobra.Command, []string) error) {
+	previous := takePersistentPreRunE(cmd)
+	if previous == nil {
+		cmd.PersistentPreRunE = preRun
+		return
+	}
+
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if err := preRun(c, args); err != nil {
+			return err
 		}
+		return previous(c, args)
+	}
+}
 
-		argsSeparatedAt := c.ArgsLenAtDash()
-		switch {
-		case argsSeparatedAt == 0 && len(args) > 0:
-			return cmd.Handle(ctx, nil, args)
-		case argsSeparatedAt > 0 && len(args[argsSeparatedAt:]) > 0:
-			return cmd.Handle(ctx, args[:argsSeparatedAt], args[argsSeparatedAt:])
-		default:
-			return cmd.Handle(ctx, args, nil)
+// takePersistentPreRunE removes the command's persistent pre run,
+// and returns it as an error returning function, if any.
+func takePersistentPreRunE(cmd *cobra.Command) func(*cobra.Command, []string) error {
+	var previous func(*cobra.Command, []string) error
+	switch {
+	case cmd.PersistentPreRunE != nil:
+		previous = cmd.PersistentPreRunE
+	case cmd.PersistentPreRun != nil:
+		run := cmd.PersistentPreRun
+		previous = func(c *cobra.Command, args []string) error {
+			run(c, args)
+			return nil
 		}
+		cmd.PersistentPreRun = nil
 	}
+	cmd.PersistentPreRunE = nil
+	return previous
 }
@@ -1,9 +1,12 @@
 package clix
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -34,6 +37,24 @@ func TestCLI_Exec(t *testing.T) {
 		assert.True(t, called)
 	})
 
+	t.Run("streams are set from options", func(t *testing.T) {
+		var out bytes.Buffer
+		cli := Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{RunE: ExecHandler(ctx, func(func()) (Handler, error) {
+				return HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					streams := IOFromContext(ctx)
+					_, err := io.Copy(streams.Out, streams.In)
+					return err
+				}), nil
+			})}, ctx, nil
+		})
+		assert.NoError(t, cli.Exec(context.Background(), []string{}, ExecWithIO(IO{
+			In:  strings.NewReader("hello"),
+			Out: &out,
+		})))
+		assert.Equal(t, "hello", out.String())
+	})
+
 	t.Run("build root command failed", func(t *testing.T) {
 		cli := Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
 			return &cobra.Command{Run: func(*cobra.Command, []string) {}}, ctx, errors.New("boum")
@@ -164,3 +185,174 @@ func Test_ExecHandler_dashed_arg_are_nil_when_handler_is_called_with_nothing_aft
 	cmd.SetArgs([]string{"a", "b", "--"})
 	require.NoError(t, cmd.Execute())
 }
+
+func Test_appendPersistentPreRunE(t *testing.T) {
+	t.Run("without previous pre run", func(t *testing.T) {
+		var cmd cobra.Command
+		called := false
+		appendPersistentPreRunE(&cmd, func(*cobra.Command, []string) error {
+			called = true
+			return nil
+		})
+		require.NoError(t, cmd.PersistentPreRunE(&cmd, nil))
+		assert.True(t, called)
+	})
+
+	t.Run("previous pre runs are called first", func(t *testing.T) {
+		var calls []string
+		cmd := cobra.Command{PersistentPreRun: func(*cobra.Command, []string) { calls = append(calls, "run") }}
+		appendPersistentPreRunE(&cmd, func(*cobra.Command, []string) error {
+			calls = append(calls, "first")
+			return nil
+		})
+		appendPersistentPreRunE(&cmd, func(*cobra.Command, []string) error {
+			calls = append(calls, "second")
+			return nil
+		})
+		assert.Nil(t, cmd.PersistentPreRun)
+		require.NoError(t, cmd.PersistentPreRunE(&cmd, nil))
+		assert.Equal(t, []string{"run", "first", "second"}, calls)
+	})
+
+	t.Run("previous pre run failed", func(t *testing.T) {
+		cmd := cobra.Command{PersistentPreRunE: func(*cobra.Command, []string) error { return errors.New("boum") }}
+		appendPersistentPreRunE(&cmd, func(*cobra.Command, []string) error {
+			t.Fatal("should not be called")
+			return nil
+		})
+		assert.Error(t, cmd.PersistentPreRunE(&cmd, nil))
+	})
+}
+
+func Test_PrependPersistentPreRunE(t *testing.T) {
+	t.Run("previous pre runs are called after", func(t *testing.T) {
+		var calls []string
+		cmd := cobra.Command{PersistentPreRun: func(*cobra.Command, []string) { calls = append(calls, "run") }}
+		PrependPersistentPreRunE(&cmd, func(*cobra.Command, []string) error {
+			calls = append(calls, "first")
+			return nil
+		})
+		PrependPersistentPreRunE(&cmd, func(*cobra.Command, []string) error {
+			calls = append(calls, "second")
+			return nil
+		})
+		assert.Nil(t, cmd.PersistentPreRun)
+		require.NoError(t, cmd.PersistentPreRunE(&cmd, nil))
+		assert.Equal(t, []string{"second", "first", "run"}, calls)
+	})
+
+	t.Run("pre run failed", func(t *testing.T) {
+		cmd := cobra.Command{PersistentPreRunE: func(*cobra.Command, []string) error {
+			t.Fatal("should not be called")
+			return nil
+		}}
+		PrependPersistentPreRunE(&cmd, func(*cobra.Command, []string) error { return errors.New("boum") })
+		assert.Error(t, cmd.PersistentPreRunE(&cmd, nil))
+	})
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// testCommand returns a builder of a command running the handler, if any,
+// set up by the provided functions, like the ones defining flags.
+func testCommand(use string, handle HandlerFunc, setups ...func(cmd *cobra.Command) error) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		cmd := &cobra.Command{Use: use}
+		if handle != nil {
+			cmd.RunE = ExecHandler(ctx, func(func()) (Handler, error) { return handle, nil })
+		}
+		return setupTestCommand(ctx, cmd, setups)
+	}
+}
+
+// testInvocationCommand returns a builder of a command handling its invocations with the handler.
+func testInvocationCommand(use string, handle InvocationHandlerFunc, setups ...func(cmd *cobra.Command) error) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		cmd := &cobra.Command{
+			Use:  use,
+			RunE: ExecInvocationHandler(ctx, func(func()) (InvocationHandler, error) { return handle, nil }),
+		}
+		return setupTestCommand(ctx, cmd, setups)
+	}
+}
+
+func setupTestCommand(ctx context.Context, cmd *cobra.Command, setups []func(cmd *cobra.Command) error) (*cobra.Command, context.Context, error) {
+	for _, setup := range setups {
+		if err := setup(cmd); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cmd, ctx, nil
+}
+
+// recordInvocation returns an invocation handler storing the invocation it handles.
+func recordInvocation(inv *Invocation) InvocationHandlerFunc {
+	return func(_ context.Context, i Invocation) error {
+		*inv = i
+		return nil
+	}
+}
+
+// noopHandler is a handler doing nothing.
+func noopHandler(context.Context, []string, []string) error { return nil }
+
+// silenceErrors sets up a command to not print the errors it returns.
+func silenceErrors(cmd *cobra.Command) error {
+	cmd.SilenceErrors = true
+	return nil
+}
+
+func Test_closeAfterExec(t *testing.T) {
+	var closed []string
+	newCloser := func(name string, err error) io.Closer {
+		return closerFunc(func() error {
+			closed = append(closed, name)
+			return err
+		})
+	}
+
+	register := func(closers ...io.Closer) HandlerFunc {
+		return func(ctx context.Context, _, _ []string) error {
+			for _, closer := range closers {
+				closeAfterExec(ctx, closer)
+			}
+			assert.Empty(t, closed)
+			return nil
+		}
+	}
+
+	err := Command(testCommand("app", register(newCloser("a", nil), newCloser("b", nil)))).Exec(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, closed)
+
+	closed = nil
+	err = Command(testCommand("app", register(newCloser("a", errors.New("boum")), newCloser("b", nil)))).Exec(context.Background(), nil)
+	assert.EqualError(t, err, "unable to close resource: boum")
+	assert.Equal(t, []string{"b", "a"}, closed)
+
+	closeAfterExec(context.Background(), newCloser("ignored", nil))
+	assert.NoError(t, CloseResources(context.Background()))
+}
+
+func Test_CloseResources(t *testing.T) {
+	closed := 0
+	cmd, ctx, err := Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {
+			closeAfterExec(ctx, closerFunc(func() error {
+				closed++
+				return nil
+			}))
+		}}, ctx, nil
+	}).Build()(context.Background())
+	require.NoError(t, err)
+
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Zero(t, closed)
+
+	require.NoError(t, CloseResources(ctx))
+	require.NoError(t, CloseResources(ctx))
+	assert.Equal(t, 1, closed)
+}
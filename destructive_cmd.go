@@ -0,0 +1,167 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ctxKeyDestructive     ctxKey = "destructive"
+	annotationDestructive        = "clix_destructive"
+)
+
+// Errors returned when a destructive command is not confirmed.
+var (
+	ErrConfirmationRequired = errors.New("destructive command requires a confirmation")
+	ErrConfirmationDeclined = errors.New("destructive command was not confirmed")
+)
+
+type destructiveCommand struct {
+	Message     string `json:"message"`
+	ResourceArg string `json:"resource_arg,omitempty"`
+	Flag        string `json:"flag"`
+}
+
+// WithDestructive marks the command as destructive: before the handler is called,
+// the user is asked for a confirmation, unless the added --yes flag, renamed with
+// DestructiveWithConfirmFlag, is provided. Without a terminal to ask on, the command
+// fails, unless DestructiveWithNonInteractive says otherwise. The decision is logged
+// with the logger from the context, if any.
+func WithDestructive(cbf CommandBuilderFunc, opts ...DestructiveCommandOption) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		o := defaultDestructiveCommandOptions()
+		for _, opt := range opts {
+			opt(o)
+		}
+
+		ctx = context.WithValue(ctx, ctxKeyDestructive, o)
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+
+		if err := checkFlagAvailable(cmd, o.confirmFlag, o.confirmFlagShorthand); err != nil {
+			return nil, nil, fmt.Errorf("unable to add confirmation flag: %w", err)
+		}
+
+		raw, err := json.Marshal(destructiveCommand{Message: o.message, ResourceArg: o.resourceArg, Flag: o.confirmFlag})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to serialize destructive declaration: %w", err)
+		}
+		setAnnotation(cmd, annotationDestructive, string(raw))
+
+		cmd.Flags().BoolP(o.confirmFlag, o.confirmFlagShorthand, false, "confirm the destructive operation without being asked")
+
+		help := "  this command is destructive, a confirmation is asked unless --" + o.confirmFlag + " is provided"
+		if o.resourceArg != "" {
+			help = "  this command is destructive, the " + o.resourceArg + " argument has to be typed\n" +
+				"  to confirm unless --" + o.confirmFlag + " is provided"
+		}
+		addHelpSection(cmd, "Confirmation", help)
+
+		return cmd, ctx, nil
+	}
+}
+
+// checkFlagAvailable makes sure a flag can be added to the command without conflicting
+// with the ones already defined.
+func checkFlagAvailable(cmd *cobra.Command, name string, shorthand string) error {
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+		if flags.Lookup(name) != nil {
+			return fmt.Errorf("flag --%s is already defined", name)
+		}
+		if shorthand != "" && flags.ShorthandLookup(shorthand) != nil {
+			return fmt.Errorf("flag shorthand -%s is already defined", shorthand)
+		}
+	}
+	return nil
+}
+
+func destructiveFromCommand(cmd *cobra.Command) (*destructiveCommand, error) {
+	raw, declared := cmd.Annotations[annotationDestructive]
+	if !declared {
+		return nil, nil
+	}
+
+	var d destructiveCommand
+	if err := json.Unmarshal([]byte(raw), &d); err != nil {
+		return nil, fmt.Errorf("unable to deserialize destructive declaration: %w", err)
+	}
+	return &d, nil
+}
+
+func confirmDestructive(ctx context.Context, c *cobra.Command, inv Invocation) error {
+	d, err := destructiveFromCommand(c)
+	if err != nil || d == nil {
+		return err
+	}
+
+	log := LoggerFromContext(ctx)
+	if log == nil {
+		log = &logger.Noop{}
+	}
+
+	if confirmed, _ := c.Flags().GetBool(d.Flag); confirmed {
+		log.WithField("confirmation", "flag").Info("destructive command confirmed")
+		return nil
+	}
+
+	o, hasOptions := ctx.Value(ctxKeyDestructive).(*destructiveCommandOptions)
+	if !hasOptions {
+		o = defaultDestructiveCommandOptions()
+	}
+	if !o.isInteractive(inv.IO) {
+		if o.nonInteractiveConfirmed {
+			log.WithField("confirmation", "non-interactive").Info("destructive command confirmed without a terminal")
+			return nil
+		}
+		log.WithField("confirmation", "none").Warn("destructive command refused without a terminal to confirm on")
+		return fmt.Errorf("%w, use --%s to confirm", ErrConfirmationRequired, d.Flag)
+	}
+
+	confirmed, err := d.ask(inv)
+	if err != nil {
+		return fmt.Errorf("unable to ask for confirmation: %w", err)
+	}
+	if !confirmed {
+		log.WithField("confirmation", "prompt").Warn("destructive command declined")
+		return ErrConfirmationDeclined
+	}
+
+	log.WithField("confirmation", "prompt").Info("destructive command confirmed")
+	return nil
+}
+
+func (d destructiveCommand) ask(inv Invocation) (bool, error) {
+	if d.ResourceArg == "" {
+		return NewPrompter(inv.IO, 3).Confirm(d.Message+" Continue?", false)
+	}
+
+	value, isSet := inv.Values[d.ResourceArg]
+	if !isSet {
+		return false, fmt.Errorf("resource argument %s has no value", d.ResourceArg)
+	}
+	resource := fmt.Sprint(value)
+
+	// a single attempt is given, a mistyped resource name is a decline
+	_, err := NewPrompter(inv.IO, 1).Text(
+		fmt.Sprintf("%s Type %q to continue", d.Message, resource),
+		func(answer string) error {
+			if answer != resource {
+				return errors.New("answer does not match")
+			}
+			return nil
+		},
+	)
+	if errors.Is(err, ErrPromptAttemptsExceeded) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,51 @@
+package clix
+
+type destructiveCommandOptions struct {
+	message                 string
+	resourceArg             string
+	confirmFlag             string
+	confirmFlagShorthand    string
+	isInteractive           func(streams IO) bool
+	nonInteractiveConfirmed bool
+}
+
+func defaultDestructiveCommandOptions() *destructiveCommandOptions {
+	return &destructiveCommandOptions{
+		message:              "This operation is destructive and can't be undone.",
+		confirmFlag:          "yes",
+		confirmFlagShorthand: "y",
+		isInteractive:        IO.IsInTerminal,
+	}
+}
+
+// DestructiveCommandOption defines the signature of an option applier.
+type DestructiveCommandOption func(o *destructiveCommandOptions)
+
+// DestructiveWithMessage sets the message displayed when asking for a confirmation.
+func DestructiveWithMessage(message string) DestructiveCommandOption {
+	return func(o *destructiveCommandOptions) { o.message = message }
+}
+
+// DestructiveWithResourceArg requires the user to type the value of the
+// provided positional argument, declared with WithArgs, to confirm.
+func DestructiveWithResourceArg(name string) DestructiveCommandOption {
+	return func(o *destructiveCommandOptions) { o.resourceArg = name }
+}
+
+// DestructiveWithConfirmFlag sets the name and the shorthand, which can be empty,
+// of the flag confirming the operation, --yes and -y by default.
+func DestructiveWithConfirmFlag(name string, shorthand string) DestructiveCommandOption {
+	return func(o *destructiveCommandOptions) { o.confirmFlag, o.confirmFlagShorthand = name, shorthand }
+}
+
+// DestructiveWithInteractiveFunc sets the function telling whenever the user can be
+// asked for a confirmation on the streams, when the input is a terminal by default.
+func DestructiveWithInteractiveFunc(isInteractive func(streams IO) bool) DestructiveCommandOption {
+	return func(o *destructiveCommandOptions) { o.isInteractive = isInteractive }
+}
+
+// DestructiveWithNonInteractive sets whenever the operation is confirmed, or refused,
+// when the user can't be asked for a confirmation. It is refused by default.
+func DestructiveWithNonInteractive(confirmed bool) DestructiveCommandOption {
+	return func(o *destructiveCommandOptions) { o.nonInteractiveConfirmed = confirmed }
+}
@@ -0,0 +1,49 @@
+package clix
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_defaultDestructiveCommandOptions(t *testing.T) {
+	o := defaultDestructiveCommandOptions()
+	assert.NotEmpty(t, o.message)
+	assert.Empty(t, o.resourceArg)
+	assert.Equal(t, "yes", o.confirmFlag)
+	assert.Equal(t, "y", o.confirmFlagShorthand)
+	assert.False(t, o.nonInteractiveConfirmed)
+	assert.False(t, o.isInteractive(IO{In: os.Stdin}), "tests are not run in a terminal")
+}
+
+func Test_DestructiveWithMessage(t *testing.T) {
+	var o destructiveCommandOptions
+	DestructiveWithMessage("sure?")(&o)
+	assert.Equal(t, "sure?", o.message)
+}
+
+func Test_DestructiveWithResourceArg(t *testing.T) {
+	var o destructiveCommandOptions
+	DestructiveWithResourceArg("name")(&o)
+	assert.Equal(t, "name", o.resourceArg)
+}
+
+func Test_DestructiveWithConfirmFlag(t *testing.T) {
+	var o destructiveCommandOptions
+	DestructiveWithConfirmFlag("force", "f")(&o)
+	assert.Equal(t, "force", o.confirmFlag)
+	assert.Equal(t, "f", o.confirmFlagShorthand)
+}
+
+func Test_DestructiveWithInteractiveFunc(t *testing.T) {
+	var o destructiveCommandOptions
+	DestructiveWithInteractiveFunc(func(IO) bool { return true })(&o)
+	assert.True(t, o.isInteractive(IO{}))
+}
+
+func Test_DestructiveWithNonInteractive(t *testing.T) {
+	var o destructiveCommandOptions
+	DestructiveWithNonInteractive(true)(&o)
+	assert.True(t, o.nonInteractiveConfirmed)
+}
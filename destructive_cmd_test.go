@@ -0,0 +1,192 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func destructiveWithInteractive(interactive bool) DestructiveCommandOption {
+	return DestructiveWithInteractiveFunc(func(IO) bool { return interactive })
+}
+
+func Test_WithDestructive(t *testing.T) {
+	newCLI := func(called *bool, log *logger.InMemory, opts ...DestructiveCommandOption) *CLI {
+		return Command(WithLogger(testCommand("app", nil), inMemoryLoggerCreateFunc(log))).
+			SubCommand(WithDestructive(WithArgs(testCommand("delete", func(context.Context, []string, []string) error {
+				*called = true
+				return nil
+			}), Arg{Name: "database", Required: true}), opts...))
+	}
+
+	for name, test := range map[string]struct {
+		interactive    bool
+		args           []string
+		input          string
+		opts           []DestructiveCommandOption
+		expectedErr    error
+		expectedCalled bool
+		expectedPrompt string
+		expectedLog    string
+	}{
+		"confirmed by flag": {
+			args:           []string{"delete", "db", "--yes"},
+			expectedCalled: true,
+			expectedLog:    "flag",
+		},
+		"confirmed by short flag when interactive": {
+			interactive:    true,
+			args:           []string{"delete", "db", "-y"},
+			expectedCalled: true,
+			expectedLog:    "flag",
+		},
+		"non interactive without flag": {
+			args:        []string{"delete", "db"},
+			expectedErr: ErrConfirmationRequired,
+			expectedLog: "none",
+		},
+		"confirmed without terminal": {
+			args:           []string{"delete", "db"},
+			opts:           []DestructiveCommandOption{DestructiveWithNonInteractive(true)},
+			expectedCalled: true,
+			expectedLog:    "non-interactive",
+		},
+		"confirmed by renamed flag": {
+			args:           []string{"delete", "db", "--force"},
+			opts:           []DestructiveCommandOption{DestructiveWithConfirmFlag("force", "")},
+			expectedCalled: true,
+			expectedLog:    "flag",
+		},
+		"confirmed by prompt": {
+			interactive:    true,
+			args:           []string{"delete", "db"},
+			input:          "y\n",
+			opts:           []DestructiveCommandOption{DestructiveWithMessage("Data will be lost.")},
+			expectedCalled: true,
+			expectedPrompt: "Data will be lost. Continue? [y/N]: ",
+			expectedLog:    "prompt",
+		},
+		"declined by prompt": {
+			interactive:    true,
+			args:           []string{"delete", "db"},
+			input:          "\n",
+			expectedErr:    ErrConfirmationDeclined,
+			expectedPrompt: "This operation is destructive and can't be undone. Continue? [y/N]: ",
+			expectedLog:    "prompt",
+		},
+		"resource typed": {
+			interactive:    true,
+			args:           []string{"delete", "db"},
+			input:          "db\n",
+			opts:           []DestructiveCommandOption{DestructiveWithResourceArg("database")},
+			expectedCalled: true,
+			expectedPrompt: `This operation is destructive and can't be undone. Type "db" to continue: `,
+			expectedLog:    "prompt",
+		},
+		"resource mistyped": {
+			interactive: true,
+			args:        []string{"delete", "db"},
+			input:       "bd\ndb\n",
+			opts:        []DestructiveCommandOption{DestructiveWithResourceArg("database")},
+			expectedErr: ErrConfirmationDeclined,
+			expectedLog: "prompt",
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+
+			var (
+				called bool
+				stderr bytes.Buffer
+				log    = logger.NewInMemory(logger.LevelInfo)
+			)
+
+			err := newCLI(&called, log, append(test.opts, destructiveWithInteractive(test.interactive))...).Exec(context.Background(), test.args, ExecWithIO(IO{
+				In:  strings.NewReader(test.input),
+				Out: ioutil.Discard,
+				Err: &stderr,
+			}))
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr), err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedCalled, called)
+			if test.expectedPrompt != "" {
+				assert.True(t, strings.HasPrefix(stderr.String(), test.expectedPrompt), stderr.String())
+			}
+			require.Len(t, log.Entries, 1)
+			assert.Equal(t, test.expectedLog, log.Entries[0].Fields["confirmation"])
+		})
+	}
+
+	t.Run("unknown resource argument", func(t *testing.T) {
+		var called bool
+		err := newCLI(&called, logger.NewInMemory(logger.LevelInfo), DestructiveWithResourceArg("table"), destructiveWithInteractive(true)).
+			Exec(context.Background(), []string{"delete", "db"}, ExecWithIO(IO{
+				In: strings.NewReader("db\n"), Out: ioutil.Discard, Err: ioutil.Discard,
+			}))
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("non interactive error names the flag", func(t *testing.T) {
+		var called bool
+		err := newCLI(&called, logger.NewInMemory(logger.LevelInfo), DestructiveWithConfirmFlag("force", ""), destructiveWithInteractive(false)).
+			Exec(context.Background(), []string{"delete", "db"}, ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}))
+		assert.EqualError(t, err, "destructive command requires a confirmation, use --force to confirm")
+	})
+
+	t.Run("help describes the confirmation", func(t *testing.T) {
+		var (
+			called bool
+			out    bytes.Buffer
+		)
+		require.NoError(t, newCLI(&called, logger.NewInMemory(logger.LevelInfo)).
+			Exec(context.Background(), []string{"delete", "--help"}, ExecWithIO(IO{Out: &out})))
+		assert.Contains(t, out.String(), "Confirmation:\n  this command is destructive")
+		assert.Contains(t, out.String(), "-y, --yes")
+	})
+}
+
+func Test_WithDestructive_flagConflict(t *testing.T) {
+	for name, define := range map[string]func(cmd *cobra.Command){
+		"name":                 func(cmd *cobra.Command) { cmd.Flags().String("yes", "", "") },
+		"shorthand":            func(cmd *cobra.Command) { cmd.Flags().BoolP("yesterday", "y", false, "") },
+		"persistent shorthand": func(cmd *cobra.Command) { cmd.PersistentFlags().BoolP("yesterday", "y", false, "") },
+	} {
+		define := define
+		t.Run(name, func(t *testing.T) {
+			_, _, err := WithDestructive(testCommand("delete", nil, func(cmd *cobra.Command) error {
+				define(cmd)
+				return nil
+			}))(context.Background())
+			assert.Error(t, err)
+		})
+	}
+
+	_, _, err := WithDestructive(testCommand("delete", nil, func(cmd *cobra.Command) error {
+		cmd.Flags().BoolP("yesterday", "y", false, "")
+		return nil
+	}), DestructiveWithConfirmFlag("yes", ""))(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_WithDestructive_withoutLogger(t *testing.T) {
+	var called bool
+	err := Command(WithDestructive(testCommand("delete", func(context.Context, []string, []string) error {
+		called = true
+		return nil
+	}))).Exec(context.Background(), []string{"--yes"})
+	require.NoError(t, err)
+	assert.True(t, called)
+}
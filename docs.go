@@ -0,0 +1,162 @@
+package clix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// DocsFormat defines the format of the generated reference documentation.
+type DocsFormat string
+
+// Formats of the generated reference documentation.
+const (
+	DocsFormatMarkdown DocsFormat = "markdown"
+	DocsFormatMan      DocsFormat = "man"
+)
+
+// GenerateDocs builds the command line interface and writes in the provided directory
+// a reference documentation file for every available command, including positional
+// arguments, flags added by decorators, flag constraints and environment bindings.
+// It is designed to be called by go generate.
+func GenerateDocs(ctx context.Context, cli *CLI, format DocsFormat, dir string) error {
+	cmd, _, err := cli.Build()(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to build command: %w", err)
+	}
+	return generateDocs(cmd, format, dir)
+}
+
+// DocsCommand builds a hidden docs command generating
+// the reference documentation of the whole command tree.
+func DocsCommand(ctx context.Context) (*cobra.Command, context.Context, error) {
+	var (
+		format = string(DocsFormatMarkdown)
+		dir    = "docs"
+	)
+
+	cmd := &cobra.Command{
+		Use:    "docs",
+		Short:  "Generate the reference documentation",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+	}
+	cmd.RunE = ExecHandler(ctx, func(func()) (Handler, error) {
+		return HandlerFunc(func(context.Context, []string, []string) error {
+			return generateDocs(cmd.Root(), DocsFormat(format), dir)
+		}), nil
+	})
+
+	cmd.Flags().StringVar(&format, "format", format, "format of the documentation, one of markdown|man")
+	cmd.Flags().StringVar(&dir, "dir", dir, "directory to write the documentation to")
+
+	return cmd, ctx, nil
+}
+
+func generateDocs(root *cobra.Command, format DocsFormat, dir string) error {
+	var (
+		write    func(w io.Writer, cmd *cobra.Command) error
+		filename func(cmd *cobra.Command) string
+	)
+
+	switch format {
+	case DocsFormatMarkdown:
+		write, filename = writeMarkdownDocs, markdownDocsFilename
+	case DocsFormatMan:
+		write, filename = writeManDocs, manDocsFilename
+	default:
+		return fmt.Errorf("unknown documentation format %q", format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { // nolint: gosec
+		return fmt.Errorf("unable to create documentation directory: %w", err)
+	}
+
+	for _, cmd := range documentedCommands(root) {
+		path := filepath.Join(dir, filename(cmd))
+		if err := writeDocsFile(path, cmd, write); err != nil {
+			return fmt.Errorf("unable to write documentation of %q: %w", cmd.CommandPath(), err)
+		}
+	}
+	return nil
+}
+
+func writeDocsFile(path string, cmd *cobra.Command, write func(w io.Writer, cmd *cobra.Command) error) error {
+	file, err := os.Create(path) // nolint: gosec
+	if err != nil {
+		return fmt.Errorf("unable to create file %q: %w", path, err)
+	}
+
+	if err := write(file, cmd); err != nil {
+		file.Close() // nolint: errcheck, gosec
+		return err
+	}
+	return file.Close()
+}
+
+// documentedCommands returns the command and its available
+// subcommands, recursively, with their default flags set.
+func documentedCommands(cmd *cobra.Command) []*cobra.Command {
+	cmd.InitDefaultHelpFlag()
+	cmd.InitDefaultVersionFlag()
+
+	commands := []*cobra.Command{cmd}
+	for _, sub := range documentedSubCommands(cmd) {
+		commands = append(commands, documentedCommands(sub)...)
+	}
+	return commands
+}
+
+func documentedSubCommands(cmd *cobra.Command) []*cobra.Command {
+	var commands []*cobra.Command
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() || sub.IsAdditionalHelpTopicCommand() {
+			commands = append(commands, sub)
+		}
+	}
+	return commands
+}
+
+// docsEnv is an environment variable bound to a flag.
+type docsEnv struct {
+	name string
+	flag string
+}
+
+func docsEnvOf(cmd *cobra.Command) []docsEnv {
+	var env []docsEnv
+	visit := func(flag *pflag.Flag) {
+		if name, isBound := flagEnv(flag); isBound && !flag.Hidden {
+			env = append(env, docsEnv{name: name, flag: flag.Name})
+		}
+	}
+	cmd.LocalFlags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+	return env
+}
+
+// docsFlagDefault returns the flag default value, or an empty string
+// if the default value is the zero value of the flag type.
+func docsFlagDefault(flag *pflag.Flag) string {
+	switch flag.DefValue {
+	case "", "false", "0", "0s", "[]", "map[]", "<nil>":
+		return ""
+	}
+	if flag.Value.Type() == "string" {
+		return fmt.Sprintf("%q", flag.DefValue)
+	}
+	return flag.DefValue
+}
+
+func docsCommandLongOrShort(cmd *cobra.Command) string {
+	if cmd.Long != "" {
+		return strings.TrimSpace(cmd.Long)
+	}
+	return strings.TrimSpace(cmd.Short)
+}
@@ -0,0 +1,145 @@
+package clix
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const manDocsSection = "1"
+
+func manDocsName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+}
+
+func manDocsFilename(cmd *cobra.Command) string {
+	return manDocsName(cmd) + "." + manDocsSection
+}
+
+func writeManDocs(w io.Writer, cmd *cobra.Command) error {
+	args, _, err := argsFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	constraints, err := flagConstraintsFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, ".TH \"%s\" \"%s\" \"\" \"%s\" \"\"\n",
+		roffEscape(strings.ToUpper(manDocsName(cmd))), manDocsSection, roffEscape(cmd.Root().Name()),
+	)
+
+	buf.WriteString(".SH NAME\n")
+	fmt.Fprintf(&buf, "%s", roffEscape(manDocsName(cmd)))
+	if cmd.Short != "" {
+		fmt.Fprintf(&buf, " \\- %s", roffEscape(cmd.Short))
+	}
+	buf.WriteString("\n")
+
+	if cmd.Runnable() {
+		fmt.Fprintf(&buf, ".SH SYNOPSIS\n\\fB%s\\fP", roffEscape(cmd.CommandPath()))
+		if use := strings.TrimPrefix(cmd.UseLine(), cmd.CommandPath()); use != "" {
+			buf.WriteString(roffEscape(use))
+		}
+		buf.WriteString("\n")
+	}
+
+	if description := docsCommandLongOrShort(cmd); description != "" {
+		fmt.Fprintf(&buf, ".SH DESCRIPTION\n%s\n", roffEscape(description))
+	}
+
+	if len(args) > 0 {
+		buf.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range args {
+			fmt.Fprintf(&buf, ".TP\n\\fB%s\\fP \\fI%s\\fP\n", roffEscape(arg.useLine()), roffEscape(arg.typeName()))
+			if arg.Usage != "" {
+				fmt.Fprintf(&buf, "%s\n", roffEscape(arg.Usage))
+			}
+		}
+	}
+
+	writeManFlags(&buf, "OPTIONS", cmd.NonInheritedFlags())
+	writeManFlags(&buf, "OPTIONS INHERITED FROM PARENT COMMANDS", cmd.InheritedFlags())
+
+	if len(constraints) > 0 {
+		buf.WriteString(".SH FLAG CONSTRAINTS\n")
+		for _, constraint := range constraints {
+			fmt.Fprintf(&buf, ".IP \\(bu 2\n%s\n", roffEscape(constraint.String()))
+		}
+	}
+
+	if cmd.HasExample() {
+		fmt.Fprintf(&buf, ".SH EXAMPLES\n.nf\n%s\n.fi\n", roffEscape(strings.TrimRight(cmd.Example, "\n")))
+	}
+
+	if env := docsEnvOf(cmd); len(env) > 0 {
+		buf.WriteString(".SH ENVIRONMENT\n")
+		for _, e := range env {
+			fmt.Fprintf(&buf, ".TP\n\\fB%s\\fP\nDefault value of \\fB\\-\\-%s\\fP.\n", roffEscape(e.name), roffEscape(e.flag))
+		}
+	}
+
+	var related []string
+	if parent := cmd.Parent(); parent != nil {
+		related = append(related, fmt.Sprintf("\\fB%s\\fP(%s)", roffEscape(manDocsName(parent)), manDocsSection))
+	}
+	for _, sub := range documentedSubCommands(cmd) {
+		related = append(related, fmt.Sprintf("\\fB%s\\fP(%s)", roffEscape(manDocsName(sub)), manDocsSection))
+	}
+	if len(related) > 0 {
+		fmt.Fprintf(&buf, ".SH SEE ALSO\n%s\n", strings.Join(related, ", "))
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func writeManFlags(buf *bytes.Buffer, title string, flags *pflag.FlagSet) {
+	if !flags.HasAvailableFlags() {
+		return
+	}
+
+	fmt.Fprintf(buf, ".SH %s\n", title)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+
+		buf.WriteString(".TP\n")
+		if flag.Shorthand != "" && flag.ShorthandDeprecated == "" {
+			fmt.Fprintf(buf, "\\fB\\-%s\\fP, ", roffEscape(flag.Shorthand))
+		}
+		fmt.Fprintf(buf, "\\fB\\-\\-%s\\fP", roffEscape(flag.Name))
+
+		varname, usage := pflag.UnquoteUsage(flag)
+		if varname != "" {
+			fmt.Fprintf(buf, "=\\fI%s\\fP", roffEscape(varname))
+		}
+		buf.WriteString("\n")
+
+		if def := docsFlagDefault(flag); def != "" {
+			usage += " (default " + def + ")"
+		}
+		fmt.Fprintf(buf, "%s\n", roffEscape(usage))
+	})
+}
+
+// roffEscape escapes text to be displayed as is by roff.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeManDocs(t *testing.T) {
+	root, _, err := newDocsTestCLI().Build()(context.Background())
+	require.NoError(t, err)
+	commands := documentedCommands(root)
+	require.Len(t, commands, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeManDocs(&buf, commands[1]))
+	assert.Equal(t, `.TH "APP\-DEPLOY" "1" "" "app" ""
+.SH NAME
+app\-deploy \- Deploy the app
+.SH SYNOPSIS
+\fBapp deploy\fP <env> [flags]
+.SH DESCRIPTION
+Deploy the app to the provided environment.
+.SH ARGUMENTS
+.TP
+\fB<env>\fP \fIenum(dev|prod)\fP
+target environment
+.SH OPTIONS
+.TP
+\fB\-\-force\fP
+skip checks
+.TP
+\fB\-h\fP, \fB\-\-help\fP
+help for deploy
+.TP
+\fB\-\-region\fP=\fIstring\fP
+region to deploy to (default "us")
+.SH OPTIONS INHERITED FROM PARENT COMMANDS
+.TP
+\fB\-o\fP, \fB\-\-output\fP=\fIformat\fP
+format to print results with, one of json|yaml|table|template=<go\-template> (default table)
+.SH FLAG CONSTRAINTS
+.IP \(bu 2
+\-\-force requires \-\-region
+.SH EXAMPLES
+.nf
+app deploy prod \-\-region eu
+.fi
+.SH ENVIRONMENT
+.TP
+\fBAPP_REGION\fP
+Default value of \fB\-\-region\fP.
+.SH SEE ALSO
+\fBapp\fP(1)
+`, buf.String())
+}
+
+func Test_manDocsFilename(t *testing.T) {
+	root, _, err := newDocsTestCLI().Build()(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "app.1", manDocsFilename(root))
+	assert.Equal(t, "app-deploy.1", manDocsFilename(root.Commands()[0]))
+}
+
+func Test_roffEscape(t *testing.T) {
+	assert.Equal(t, `a\-b \ec`, roffEscape(`a-b \c`))
+	assert.Equal(t, "\\&.TH\nline\n\\&'quoted", roffEscape(".TH\nline\n'quoted"))
+}
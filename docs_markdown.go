@@ -0,0 +1,95 @@
+package clix
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func markdownDocsFilename(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".md"
+}
+
+func writeMarkdownDocs(w io.Writer, cmd *cobra.Command) error {
+	args, _, err := argsFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	constraints, err := flagConstraintsFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s\n\n", cmd.CommandPath())
+	if description := docsCommandLongOrShort(cmd); description != "" {
+		fmt.Fprintf(&buf, "%s\n\n", description)
+	}
+
+	if cmd.Runnable() {
+		fmt.Fprintf(&buf, "## Synopsis\n\n```\n%s\n```\n\n", cmd.UseLine())
+	}
+
+	if len(args) > 0 {
+		buf.WriteString("## Arguments\n\n| Name | Type | Required | Description |\n| --- | --- | --- | --- |\n")
+		for _, arg := range args {
+			fmt.Fprintf(&buf, "| `%s` | %s | %t | %s |\n",
+				arg.useLine(), markdownCell(arg.typeName()), arg.Required, markdownCell(arg.Usage),
+			)
+		}
+		buf.WriteString("\n")
+	}
+
+	if cmd.HasExample() {
+		fmt.Fprintf(&buf, "## Examples\n\n```\n%s\n```\n\n", strings.TrimRight(cmd.Example, "\n"))
+	}
+
+	if flags := cmd.NonInheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&buf, "## Options\n\n```\n%s```\n\n", flags.FlagUsages())
+	}
+	if flags := cmd.InheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&buf, "## Options inherited from parent commands\n\n```\n%s```\n\n", flags.FlagUsages())
+	}
+
+	if len(constraints) > 0 {
+		buf.WriteString("## Flag constraints\n\n")
+		for _, constraint := range constraints {
+			fmt.Fprintf(&buf, "* %s\n", constraint)
+		}
+		buf.WriteString("\n")
+	}
+
+	if env := docsEnvOf(cmd); len(env) > 0 {
+		buf.WriteString("## Environment\n\n| Variable | Flag |\n| --- | --- |\n")
+		for _, e := range env {
+			fmt.Fprintf(&buf, "| `%s` | `--%s` |\n", e.name, e.flag)
+		}
+		buf.WriteString("\n")
+	}
+
+	subcommands := documentedSubCommands(cmd)
+	if cmd.HasParent() || len(subcommands) > 0 {
+		buf.WriteString("## See also\n\n")
+		if parent := cmd.Parent(); parent != nil {
+			fmt.Fprintf(&buf, "* [%s](%s) - %s\n", parent.CommandPath(), markdownDocsFilename(parent), parent.Short)
+		}
+		for _, sub := range subcommands {
+			fmt.Fprintf(&buf, "* [%s](%s) - %s\n", sub.CommandPath(), markdownDocsFilename(sub), sub.Short)
+		}
+		buf.WriteString("\n")
+	}
+
+	_, err = w.Write(bytes.TrimRight(buf.Bytes(), "\n"))
+	if err == nil {
+		_, err = io.WriteString(w, "\n")
+	}
+	return err
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,51 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeMarkdownDocs(t *testing.T) {
+	root, _, err := newDocsTestCLI().Build()(context.Background())
+	require.NoError(t, err)
+	commands := documentedCommands(root)
+	require.Len(t, commands, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeMarkdownDocs(&buf, commands[1]))
+	assert.Equal(t, "# app deploy\n\n"+
+		"Deploy the app to the provided environment.\n\n"+
+		"## Synopsis\n\n```\napp deploy <env> [flags]\n```\n\n"+
+		"## Arguments\n\n"+
+		"| Name | Type | Required | Description |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| `<env>` | enum(dev\\|prod) | true | target environment |\n\n"+
+		"## Examples\n\n```\napp deploy prod --region eu\n```\n\n"+
+		"## Options\n\n```\n"+
+		"      --force           skip checks\n"+
+		"  -h, --help            help for deploy\n"+
+		"      --region string   region to deploy to (default \"us\")\n"+
+		"```\n\n"+
+		"## Options inherited from parent commands\n\n```\n"+
+		"  -o, --output format   format to print results with, one of json|yaml|table|template=<go-template> (default table)\n"+
+		"```\n\n"+
+		"## Flag constraints\n\n* --force requires --region\n\n"+
+		"## Environment\n\n"+
+		"| Variable | Flag |\n"+
+		"| --- | --- |\n"+
+		"| `APP_REGION` | `--region` |\n\n"+
+		"## See also\n\n* [app](app.md) - App does things\n",
+		buf.String(),
+	)
+}
+
+func Test_markdownDocsFilename(t *testing.T) {
+	root, _, err := newDocsTestCLI().Build()(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "app.md", markdownDocsFilename(root))
+	assert.Equal(t, "app_deploy.md", markdownDocsFilename(root.Commands()[0]))
+}
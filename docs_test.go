@@ -0,0 +1,105 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDocsTestCLI() *CLI {
+	return Command(WithOutput(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{Use: "app", Short: "App does things"}, ctx, nil
+	})).SubCommand(WithFlagConstraints(WithArgs(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		cmd := &cobra.Command{
+			Use:     "deploy",
+			Short:   "Deploy the app",
+			Long:    "Deploy the app to the provided environment.",
+			Example: "app deploy prod --region eu",
+			Run:     func(*cobra.Command, []string) {},
+		}
+		cmd.Flags().String("region", "us", "region to deploy to")
+		cmd.Flags().Bool("force", false, "skip checks")
+		if err := BindFlagEnv(cmd.Flags(), "region", "APP_REGION"); err != nil {
+			return nil, nil, err
+		}
+		return cmd, ctx, nil
+	},
+		Arg{Name: "env", Type: ArgTypeEnum, Enum: []string{"dev", "prod"}, Required: true, Usage: "target environment"},
+	), FlagRequires("force", "region"))).
+		SubCommand(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}}, ctx, nil
+		})
+}
+
+func readDocsDir(t *testing.T, dir string) map[string]string {
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	content := make(map[string]string)
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		require.NoError(t, err)
+		content[file.Name()] = string(raw)
+	}
+	return content
+}
+
+func Test_GenerateDocs(t *testing.T) {
+	t.Run("markdown", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "docs")
+		require.NoError(t, GenerateDocs(context.Background(), newDocsTestCLI(), DocsFormatMarkdown, dir))
+
+		docs := readDocsDir(t, dir)
+		assert.Len(t, docs, 2)
+		assert.Contains(t, docs, "app.md")
+		assert.Contains(t, docs, "app_deploy.md")
+	})
+
+	t.Run("man", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, GenerateDocs(context.Background(), newDocsTestCLI(), DocsFormatMan, dir))
+
+		docs := readDocsDir(t, dir)
+		assert.Len(t, docs, 2)
+		assert.Contains(t, docs, "app.1")
+		assert.Contains(t, docs, "app-deploy.1")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		assert.Error(t, GenerateDocs(context.Background(), newDocsTestCLI(), "html", t.TempDir()))
+	})
+
+	t.Run("directory can't be created", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, ioutil.WriteFile(file, nil, 0o600))
+		assert.Error(t, GenerateDocs(context.Background(), newDocsTestCLI(), DocsFormatMan, file))
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		err := GenerateDocs(context.Background(), Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		}), DocsFormatMarkdown, t.TempDir())
+		assert.Error(t, err)
+	})
+}
+
+func Test_DocsCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	err := newDocsTestCLI().SubCommand(DocsCommand).Exec(context.Background(), []string{"docs", "--format", "man", "--dir", dir})
+	require.NoError(t, err)
+
+	docs := readDocsDir(t, dir)
+	assert.Len(t, docs, 2, "docs command is hidden")
+	assert.Contains(t, docs["app.1"], `\fB\-o\fP, \fB\-\-output\fP=\fIformat\fP`)
+
+	_, err = os.Stat(filepath.Join(dir, "app-docs.1"))
+	assert.True(t, os.IsNotExist(err))
+}
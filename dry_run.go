@@ -0,0 +1,87 @@
+package clix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+const ctxKeyDryRun ctxKey = "dry-run"
+
+// dryRun stores the dry-run mode and the side effects
+// performed, or skipped, while handling a command.
+type dryRun struct {
+	enabled bool
+	w       io.Writer
+
+	m         sync.Mutex
+	performed []string
+	skipped   []string
+}
+
+// NewDryRunContext creates a context where the dry-run mode is set as provided,
+// designed to test handlers and dependencies relying on SideEffect.
+func NewDryRunContext(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, ctxKeyDryRun, &dryRun{enabled: enabled, w: ioutil.Discard})
+}
+
+func dryRunFromContext(ctx context.Context) *dryRun {
+	if d, hasDryRun := ctx.Value(ctxKeyDryRun).(*dryRun); hasDryRun && d != nil {
+		return d
+	}
+	return nil
+}
+
+// IsDryRun returns whenever the dry-run mode is enabled in the context.
+func IsDryRun(ctx context.Context) bool {
+	d := dryRunFromContext(ctx)
+	return d != nil && d.enabled
+}
+
+// SideEffect should be called before performing a side effect, like a write
+// to a database, and returns whenever it should be performed. In dry-run mode,
+// the side effect is reported to the user instead.
+func SideEffect(ctx context.Context, description string) bool {
+	d := dryRunFromContext(ctx)
+	if d == nil {
+		return true
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.enabled {
+		d.skipped = append(d.skipped, description)
+		fmt.Fprintf(d.w, "dry-run: skipped %s\n", description) // nolint: errcheck, gosec
+		return false
+	}
+
+	d.performed = append(d.performed, description)
+	return true
+}
+
+// PerformedSideEffects returns the descriptions of the side effects performed so far.
+func PerformedSideEffects(ctx context.Context) []string {
+	d := dryRunFromContext(ctx)
+	if d == nil {
+		return nil
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+	return append([]string(nil), d.performed...)
+}
+
+// SkippedSideEffects returns the descriptions of the side effects skipped so far in dry-run mode.
+func SkippedSideEffects(ctx context.Context) []string {
+	d := dryRunFromContext(ctx)
+	if d == nil {
+		return nil
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+	return append([]string(nil), d.skipped...)
+}
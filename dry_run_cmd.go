@@ -0,0 +1,42 @@
+package clix
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// WithDryRun adds to an existing command the dry-run persistent flag, available to
+// handlers and their dependencies through IsDryRun and SideEffect. In dry-run mode,
+// a notice is written to the error stream before the command is handled.
+func WithDryRun(cbf CommandBuilderFunc) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		d := &dryRun{w: os.Stderr}
+		ctx = context.WithValue(ctx, ctxKeyDryRun, d)
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build root command: %w", err)
+		}
+
+		cmd.PersistentFlags().BoolVar(&d.enabled,
+			"dry-run", false,
+			"show what would be done, without making any change",
+		)
+		appendPersistentPreRunE(cmd, dryRunPreRunInit(d))
+
+		return cmd, ctx, nil
+	}
+}
+
+func dryRunPreRunInit(d *dryRun) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		d.w = cmd.ErrOrStderr()
+		if d.enabled {
+			fmt.Fprintln(d.w, "dry-run mode: no change will be made") // nolint: errcheck, gosec
+		}
+		return nil
+	}
+}
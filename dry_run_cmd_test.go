@@ -0,0 +1,58 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithDryRun(t *testing.T) {
+	newCLI := func(handle HandlerFunc) *CLI {
+		return Command(WithDryRun(testCommand("app", nil))).SubCommand(testCommand("migrate", handle))
+	}
+
+	t.Run("dry-run is propagated to subcommands", func(t *testing.T) {
+		var (
+			stderr    bytes.Buffer
+			performed []string
+			isDryRun  bool
+		)
+		require.NoError(t, newCLI(func(ctx context.Context, _, _ []string) error {
+			isDryRun = IsDryRun(ctx)
+			SideEffect(ctx, "apply migration 1")
+			performed = PerformedSideEffects(ctx)
+			return nil
+		}).Exec(context.Background(), []string{"migrate", "--dry-run"}, ExecWithIO(IO{Err: &stderr})))
+
+		assert.True(t, isDryRun)
+		assert.Empty(t, performed)
+		assert.Equal(t, "dry-run mode: no change will be made\ndry-run: skipped apply migration 1\n", stderr.String())
+	})
+
+	t.Run("without dry-run", func(t *testing.T) {
+		var (
+			stderr    bytes.Buffer
+			performed []string
+		)
+		require.NoError(t, newCLI(func(ctx context.Context, _, _ []string) error {
+			SideEffect(ctx, "apply migration 1")
+			performed = PerformedSideEffects(ctx)
+			return nil
+		}).Exec(context.Background(), []string{"migrate"}, ExecWithIO(IO{Err: &stderr})))
+
+		assert.Equal(t, []string{"apply migration 1"}, performed)
+		assert.Empty(t, stderr.String())
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		_, _, err := WithDryRun(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		})(context.Background())
+		assert.Error(t, err)
+	})
+}
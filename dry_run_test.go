@@ -0,0 +1,40 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsDryRun(t *testing.T) {
+	assert.False(t, IsDryRun(context.Background()))
+	assert.False(t, IsDryRun(NewDryRunContext(context.Background(), false)))
+	assert.True(t, IsDryRun(NewDryRunContext(context.Background(), true)))
+}
+
+func Test_SideEffect(t *testing.T) {
+	t.Run("without dry-run in context", func(t *testing.T) {
+		ctx := context.Background()
+		assert.True(t, SideEffect(ctx, "drop table"))
+		assert.Nil(t, PerformedSideEffects(ctx))
+		assert.Nil(t, SkippedSideEffects(ctx))
+	})
+
+	t.Run("dry-run disabled", func(t *testing.T) {
+		ctx := NewDryRunContext(context.Background(), false)
+		assert.True(t, SideEffect(ctx, "drop table"))
+		assert.Equal(t, []string{"drop table"}, PerformedSideEffects(ctx))
+		assert.Empty(t, SkippedSideEffects(ctx))
+	})
+
+	t.Run("dry-run enabled", func(t *testing.T) {
+		var out bytes.Buffer
+		ctx := context.WithValue(context.Background(), ctxKeyDryRun, &dryRun{enabled: true, w: &out})
+		assert.False(t, SideEffect(ctx, "drop table"))
+		assert.Empty(t, PerformedSideEffects(ctx))
+		assert.Equal(t, []string{"drop table"}, SkippedSideEffects(ctx))
+		assert.Equal(t, "dry-run: skipped drop table\n", out.String())
+	})
+}
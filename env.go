@@ -0,0 +1,63 @@
+package clix
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const annotationFlagEnv = "clix_env"
+
+// BindFlagEnv binds the flag to an environment variable. When executed by CLI.Exec,
+// a flag not provided on the command line is set to the value of the variable, if set,
+// as if provided on the command line: it is considered changed, and so satisfies
+// flag constraints and is not prompted for. Bindings are listed in the generated
+// documentation.
+func BindFlagEnv(flags *pflag.FlagSet, name string, env string) error {
+	return flags.SetAnnotation(name, annotationFlagEnv, []string{env})
+}
+
+func flagEnv(flag *pflag.Flag) (string, bool) {
+	if env := flag.Annotations[annotationFlagEnv]; len(env) > 0 {
+		return env[0], true
+	}
+	return "", false
+}
+
+// applyFlagsEnv makes the command and its subcommands set their flags bound to an
+// environment variable, once the command line is parsed. Only the closest persistent
+// pre run is executed, so the one of every command defining it is wrapped.
+func applyFlagsEnv(cmd *cobra.Command) {
+	if !cmd.HasParent() || cmd.PersistentPreRunE != nil || cmd.PersistentPreRun != nil {
+		PrependPersistentPreRunE(cmd, setFlagsFromEnv)
+	}
+	for _, sub := range cmd.Commands() {
+		applyFlagsEnv(sub)
+	}
+}
+
+// setFlagsFromEnv sets the flags bound to an environment variable
+// that were not provided on the command line.
+func setFlagsFromEnv(cmd *cobra.Command, _ []string) error {
+	var err error
+
+	flags := cmd.Flags()
+	flags.VisitAll(func(flag *pflag.Flag) {
+		env, isBound := flagEnv(flag)
+		if !isBound || flag.Changed || err != nil {
+			return
+		}
+		value, isSet := os.LookupEnv(env)
+		if !isSet {
+			return
+		}
+
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of environment variable %s for flag --%s: %w", value, env, flag.Name, setErr)
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,91 @@
+package clix
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BindFlagEnv(t *testing.T) {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.String("region", "", "")
+
+	require.NoError(t, BindFlagEnv(flags, "region", "APP_REGION"))
+	env, isBound := flagEnv(flags.Lookup("region"))
+	assert.True(t, isBound)
+	assert.Equal(t, "APP_REGION", env)
+
+	assert.Error(t, BindFlagEnv(flags, "unknown", "APP_UNKNOWN"))
+}
+
+func Test_applyFlagsEnv(t *testing.T) {
+	var (
+		region  string
+		tags    []string
+		retries int
+	)
+
+	cli := Command(testCommand("app", nil, func(cmd *cobra.Command) error {
+		cmd.PersistentFlags().StringVar(&region, "region", "us", "")
+		return BindFlagEnv(cmd.PersistentFlags(), "region", "APP_REGION")
+	})).SubCommand(testCommand("sub", noopHandler, func(cmd *cobra.Command) error {
+		cmd.Flags().StringSliceVar(&tags, "tag", nil, "")
+		cmd.Flags().IntVar(&retries, "retries", 0, "")
+		if err := BindFlagEnv(cmd.Flags(), "tag", "APP_TAGS"); err != nil {
+			return err
+		}
+		return BindFlagEnv(cmd.Flags(), "retries", "APP_RETRIES")
+	}))
+
+	t.Run("environment sets flags", func(t *testing.T) {
+		t.Setenv("APP_REGION", "eu")
+		t.Setenv("APP_TAGS", "a,b")
+		require.NoError(t, cli.Exec(context.Background(), []string{"sub"}))
+		assert.Equal(t, "eu", region)
+		assert.Equal(t, []string{"a", "b"}, tags)
+		assert.Equal(t, 0, retries)
+	})
+
+	t.Run("flags have precedence", func(t *testing.T) {
+		t.Setenv("APP_REGION", "eu")
+		t.Setenv("APP_TAGS", "a,b")
+		require.NoError(t, cli.Exec(context.Background(), []string{"sub", "--region", "ap", "--tag", "c"}))
+		assert.Equal(t, "ap", region)
+		assert.Equal(t, []string{"c"}, tags)
+	})
+
+	t.Run("invalid environment value", func(t *testing.T) {
+		t.Setenv("APP_RETRIES", "many")
+		err := cli.Exec(context.Background(), []string{"sub"}, ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}))
+		assert.Error(t, err)
+	})
+}
+
+func Test_applyFlagsEnv_changed(t *testing.T) {
+	var (
+		inv  Invocation
+		name string
+	)
+
+	newCLI := func(preRun bool) *CLI {
+		return Command(testCommand("app", nil)).SubCommand(WithFlagConstraints(testInvocationCommand("sub", recordInvocation(&inv), func(cmd *cobra.Command) error {
+			if preRun {
+				cmd.PersistentPreRun = func(*cobra.Command, []string) {}
+			}
+			cmd.Flags().StringVar(&name, "name", "", "")
+			return BindFlagEnv(cmd.Flags(), "name", "APP_NAME")
+		}), FlagsRequired("name")))
+	}
+
+	for _, preRun := range []bool{false, true} {
+		t.Setenv("APP_NAME", "bob")
+		require.NoError(t, newCLI(preRun).Exec(context.Background(), []string{"sub"}))
+		assert.Equal(t, "bob", name)
+		assert.Equal(t, map[string]string{"name": "bob"}, inv.ChangedFlags)
+	}
+}
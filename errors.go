@@ -0,0 +1,11 @@
+package clix
+
+// UsageError is returned when a command is not used the way it is supposed to.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *UsageError) Unwrap() error { return e.Err }
@@ -0,0 +1,15 @@
+package clix
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageError(t *testing.T) {
+	cause := errors.New("boum")
+	err := error(&UsageError{Err: cause})
+	assert.Equal(t, "boum", err.Error())
+	assert.True(t, errors.Is(err, cause))
+}
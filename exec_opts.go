@@ -0,0 +1,43 @@
+package clix
+
+type execOptions struct {
+	io        IO
+	flagFiles *flagFileOptions
+	argsFiles *argsFileOptions
+}
+
+// ExecOption defines the signature of an option applier.
+type ExecOption func(o *execOptions)
+
+// ExecWithIO sets the streams used by the executed commands,
+// unset streams default to the standard ones.
+func ExecWithIO(streams IO) ExecOption {
+	return func(o *execOptions) { o.io = streams }
+}
+
+// ExecWithFlagFiles makes every string flag of the command tree accept "@path" to read
+// the value from a file, and "@-" to read it from the input stream. Values starting
+// with "@" can still be provided literally by doubling it, like "@@value".
+func ExecWithFlagFiles(opts ...FlagFileOption) ExecOption {
+	return func(o *execOptions) {
+		o.flagFiles = defaultFlagFileOptions()
+		for _, opt := range opts {
+			opt(o.flagFiles)
+		}
+	}
+}
+
+// ExecWithArgsFiles expands the arguments naming an argument file, like "@args.txt",
+// into the arguments the file contains, before they are parsed. Arguments in files are
+// separated by spaces or new lines, can be quoted like in a shell, and lines starting
+// with # are comments. Argument files can reference other argument files, and
+// arguments after a double dash are never expanded. Exec fails if the prefix starts with "@"
+// while flag values can be read from files, with ExecWithFlagFiles or Secret flags.
+func ExecWithArgsFiles(opts ...ArgsFileOption) ExecOption {
+	return func(o *execOptions) {
+		o.argsFiles = defaultArgsFileOptions()
+		for _, opt := range opts {
+			opt(o.argsFiles)
+		}
+	}
+}
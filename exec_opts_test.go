@@ -0,0 +1,32 @@
+package clix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExecWithIO(t *testing.T) {
+	var (
+		o   execOptions
+		out bytes.Buffer
+	)
+	ExecWithIO(IO{Out: &out})(&o)
+	assert.Equal(t, IO{Out: &out}, o.io)
+}
+
+func Test_ExecWithFlagFiles_options(t *testing.T) {
+	var o execOptions
+	ExecWithFlagFiles()(&o)
+	assert.Equal(t, int64(1<<20), o.flagFiles.maxSize)
+
+	ExecWithFlagFiles(FlagFileWithMaxSize(42))(&o)
+	assert.Equal(t, int64(42), o.flagFiles.maxSize)
+}
+
+func Test_ExecWithArgsFiles_options(t *testing.T) {
+	var o execOptions
+	ExecWithArgsFiles(ArgsFileWithPrefix("+"))(&o)
+	assert.Equal(t, &argsFileOptions{prefix: "+", maxDepth: 8}, o.argsFiles)
+}
@@ -0,0 +1,34 @@
+package clix
+
+import (
+	"errors"
+)
+
+// ExitCoder is implemented by errors carrying the exit code the process should exit with.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// Exit codes of errors returned by clix.
+const (
+	ExitCodeSuccess = 0
+	ExitCodeFailure = 1
+	ExitCodePanic   = 70
+	ExitCodeTimeout = 124
+)
+
+// ExitCode returns the code the process should exit with, given
+// the error returned by Exec. It is designed to be used like:
+//
+//	os.Exit(clix.ExitCode(cli.Exec(ctx, os.Args[1:])))
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitCodeFailure
+}
@@ -0,0 +1,22 @@
+package clix
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type exitCodeError int
+
+func (e exitCodeError) Error() string { return "exit" }
+func (e exitCodeError) ExitCode() int { return int(e) }
+
+func Test_ExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeSuccess, ExitCode(nil))
+	assert.Equal(t, ExitCodeFailure, ExitCode(errors.New("boum")))
+	assert.Equal(t, 42, ExitCode(exitCodeError(42)))
+	assert.Equal(t, 42, ExitCode(fmt.Errorf("wrapped: %w", exitCodeError(42))))
+	assert.Equal(t, ExitCodePanic, ExitCode(&PanicError{Value: "boum"}))
+}
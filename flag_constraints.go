@@ -0,0 +1,192 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const annotationFlagConstraints = "clix_flag_constraints"
+
+// FlagConstraintKind defines the kind of relation a constraint enforces between flags.
+type FlagConstraintKind string
+
+// Kinds of flag constraints.
+const (
+	FlagConstraintRequired          FlagConstraintKind = "required"
+	FlagConstraintOneRequired       FlagConstraintKind = "one-required"
+	FlagConstraintMutuallyExclusive FlagConstraintKind = "mutually-exclusive"
+	FlagConstraintRequires          FlagConstraintKind = "requires"
+)
+
+// FlagConstraint defines a relation between flags that is validated
+// before the command handler is called.
+type FlagConstraint struct {
+	Kind  FlagConstraintKind `json:"kind"`
+	Flags []string           `json:"flags"`
+}
+
+// FlagsRequired creates a constraint that requires all provided flags to be set.
+func FlagsRequired(flags ...string) FlagConstraint {
+	return FlagConstraint{Kind: FlagConstraintRequired, Flags: flags}
+}
+
+// FlagsOneRequired creates a constraint that requires at least one of the provided flags to be set.
+func FlagsOneRequired(flags ...string) FlagConstraint {
+	return FlagConstraint{Kind: FlagConstraintOneRequired, Flags: flags}
+}
+
+// FlagsMutuallyExclusive creates a constraint that forbids more than one of the provided flags to be set.
+func FlagsMutuallyExclusive(flags ...string) FlagConstraint {
+	return FlagConstraint{Kind: FlagConstraintMutuallyExclusive, Flags: flags}
+}
+
+// FlagRequires creates a constraint that requires, when flag is set, all the required flags to be set.
+func FlagRequires(flag string, required ...string) FlagConstraint {
+	return FlagConstraint{Kind: FlagConstraintRequires, Flags: append([]string{flag}, required...)}
+}
+
+func (c FlagConstraint) String() string {
+	flags := make([]string, len(c.Flags))
+	for i, flag := range c.Flags {
+		flags[i] = "--" + flag
+	}
+
+	switch c.Kind {
+	case FlagConstraintRequired:
+		if len(flags) == 1 {
+			return flags[0] + " is required"
+		}
+		return joinFlags(flags, "and") + " are required"
+	case FlagConstraintOneRequired:
+		return "one of " + joinFlags(flags, "or") + " is required"
+	case FlagConstraintMutuallyExclusive:
+		return joinFlags(flags, "and") + " are mutually exclusive"
+	case FlagConstraintRequires:
+		return flags[0] + " requires " + joinFlags(flags[1:], "and")
+	default:
+		return fmt.Sprintf("unknown constraint %q on %s", c.Kind, joinFlags(flags, "and"))
+	}
+}
+
+func joinFlags(flags []string, conjunction string) string {
+	if len(flags) < 2 {
+		return strings.Join(flags, "")
+	}
+	return strings.Join(flags[:len(flags)-1], ", ") + " " + conjunction + " " + flags[len(flags)-1]
+}
+
+func (c FlagConstraint) validateDeclaration() error {
+	switch c.Kind {
+	case FlagConstraintRequired, FlagConstraintOneRequired:
+		if len(c.Flags) == 0 {
+			return fmt.Errorf("%s constraint requires at least one flag", c.Kind)
+		}
+	case FlagConstraintMutuallyExclusive, FlagConstraintRequires:
+		if len(c.Flags) < 2 {
+			return fmt.Errorf("%s constraint requires at least two flags", c.Kind)
+		}
+	default:
+		return fmt.Errorf("unknown constraint %q", c.Kind)
+	}
+	return nil
+}
+
+func (c FlagConstraint) validate(flags *pflag.FlagSet) error {
+	var changed []string
+	for _, name := range c.Flags {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("constraint %q is defined on unknown flag %q", c.String(), name)
+		}
+		if flag.Changed {
+			changed = append(changed, name)
+		}
+	}
+
+	var valid bool
+	switch c.Kind {
+	case FlagConstraintRequired:
+		valid = len(changed) == len(c.Flags)
+	case FlagConstraintOneRequired:
+		valid = len(changed) > 0
+	case FlagConstraintMutuallyExclusive:
+		valid = len(changed) <= 1
+	case FlagConstraintRequires:
+		valid = !flags.Changed(c.Flags[0]) || len(changed) == len(c.Flags)
+	default:
+		return fmt.Errorf("unknown constraint %q", c.Kind)
+	}
+
+	if !valid {
+		return &UsageError{Err: errors.New(c.String())}
+	}
+	return nil
+}
+
+// WithFlagConstraints adds constraints on the command flags, validated before
+// the handler is called. Constraints are displayed in the command help.
+func WithFlagConstraints(cbf CommandBuilderFunc, constraints ...FlagConstraint) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+
+		all, err := flagConstraintsFromCommand(cmd)
+		if err != nil {
+			return nil, nil, err
+		}
+		all = append(all, constraints...)
+
+		var help []string
+		for _, constraint := range all {
+			if err := constraint.validateDeclaration(); err != nil {
+				return nil, nil, fmt.Errorf("invalid flag constraint declaration: %w", err)
+			}
+			help = append(help, "  "+constraint.String())
+		}
+
+		raw, err := json.Marshal(all)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to serialize flag constraints: %w", err)
+		}
+		setAnnotation(cmd, annotationFlagConstraints, string(raw))
+		addHelpSection(cmd, "Flag constraints", strings.Join(help, "\n"))
+
+		return cmd, ctx, nil
+	}
+}
+
+// flagConstraintsFromCommand returns the flag constraints declared with WithFlagConstraints.
+func flagConstraintsFromCommand(cmd *cobra.Command) ([]FlagConstraint, error) {
+	raw, declared := cmd.Annotations[annotationFlagConstraints]
+	if !declared {
+		return nil, nil
+	}
+
+	var constraints []FlagConstraint
+	if err := json.Unmarshal([]byte(raw), &constraints); err != nil {
+		return nil, fmt.Errorf("unable to deserialize flag constraints: %w", err)
+	}
+	return constraints, nil
+}
+
+func validateFlagConstraints(cmd *cobra.Command) error {
+	constraints, err := flagConstraintsFromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, constraint := range constraints {
+		if err := constraint.validate(cmd.Flags()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
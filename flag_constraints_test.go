@@ -0,0 +1,125 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagConstraint_String(t *testing.T) {
+	assert.Equal(t, "--a is required", FlagsRequired("a").String())
+	assert.Equal(t, "--a, --b and --c are required", FlagsRequired("a", "b", "c").String())
+	assert.Equal(t, "one of --id or --name is required", FlagsOneRequired("id", "name").String())
+	assert.Equal(t, "--json and --table are mutually exclusive", FlagsMutuallyExclusive("json", "table").String())
+	assert.Equal(t, "--cert requires --key", FlagRequires("cert", "key").String())
+	assert.Equal(t, `unknown constraint "boum" on --a`, FlagConstraint{Kind: "boum", Flags: []string{"a"}}.String())
+}
+
+func TestFlagConstraint_validateDeclaration(t *testing.T) {
+	assert.NoError(t, FlagsRequired("a").validateDeclaration())
+	assert.NoError(t, FlagRequires("a", "b").validateDeclaration())
+	assert.Error(t, FlagsOneRequired().validateDeclaration())
+	assert.Error(t, FlagsMutuallyExclusive("a").validateDeclaration())
+	assert.Error(t, FlagConstraint{Kind: "boum", Flags: []string{"a"}}.validateDeclaration())
+}
+
+func TestFlagConstraint_validate(t *testing.T) {
+	for name, test := range map[string]struct {
+		constraint FlagConstraint
+		args       []string
+		valid      bool
+	}{
+		"required set":                     {constraint: FlagsRequired("a", "b"), args: []string{"--a", "--b"}, valid: true},
+		"required missing":                 {constraint: FlagsRequired("a", "b"), args: []string{"--a"}},
+		"one required set":                 {constraint: FlagsOneRequired("a", "b"), args: []string{"--b"}, valid: true},
+		"one required missing":             {constraint: FlagsOneRequired("a", "b")},
+		"mutually exclusive with one":      {constraint: FlagsMutuallyExclusive("a", "b"), args: []string{"--a"}, valid: true},
+		"mutually exclusive with both":     {constraint: FlagsMutuallyExclusive("a", "b"), args: []string{"--a", "--b"}},
+		"requires without flag":            {constraint: FlagRequires("a", "b", "c"), valid: true},
+		"requires with all required flags": {constraint: FlagRequires("a", "b", "c"), args: []string{"--a", "--b", "--c"}, valid: true},
+		"requires with missing flag":       {constraint: FlagRequires("a", "b", "c"), args: []string{"--a", "--b"}},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+			flags.Bool("a", false, "")
+			flags.Bool("b", false, "")
+			flags.Bool("c", false, "")
+			require.NoError(t, flags.Parse(test.args))
+
+			err := test.constraint.validate(flags)
+			if test.valid {
+				assert.NoError(t, err)
+				return
+			}
+			var usageErr *UsageError
+			assert.True(t, errors.As(err, &usageErr))
+		})
+	}
+
+	t.Run("unknown flag", func(t *testing.T) {
+		err := FlagsRequired("a").validate(pflag.NewFlagSet("", pflag.ContinueOnError))
+		require.Error(t, err)
+		var usageErr *UsageError
+		assert.False(t, errors.As(err, &usageErr))
+	})
+}
+
+func Test_WithFlagConstraints(t *testing.T) {
+	cli := Command(WithFlagConstraints(WithFlagConstraints(testCommand("app", noopHandler, silenceErrors, func(cmd *cobra.Command) error {
+		cmd.Flags().String("cert", "", "")
+		cmd.Flags().String("key", "", "")
+		cmd.Flags().Bool("json", false, "")
+		cmd.Flags().Bool("table", false, "")
+		return nil
+	}), FlagRequires("cert", "key")), FlagsMutuallyExclusive("json", "table")))
+
+	t.Run("constraints are respected", func(t *testing.T) {
+		require.NoError(t, cli.Exec(context.Background(), []string{"--cert", "c", "--key", "k", "--json"}))
+	})
+
+	t.Run("constraints are not respected", func(t *testing.T) {
+		var out bytes.Buffer
+		err := cli.Exec(context.Background(), []string{"--json", "--table"}, ExecWithIO(IO{Out: &out, Err: &out}))
+		var usageErr *UsageError
+		require.True(t, errors.As(err, &usageErr))
+		assert.Equal(t, "--json and --table are mutually exclusive", err.Error())
+		assert.Contains(t, out.String(), "Usage:")
+	})
+
+	t.Run("constraints are displayed in help", func(t *testing.T) {
+		cmd, _, err := cli.Build()(context.Background())
+		require.NoError(t, err)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		require.NoError(t, cmd.Usage())
+		assert.Contains(t, out.String(), "Flag constraints:\n"+
+			"  --cert requires --key\n"+
+			"  --json and --table are mutually exclusive\n")
+	})
+
+	t.Run("invalid declaration", func(t *testing.T) {
+		_, _, err := Command(WithFlagConstraints(testCommand("app", nil), FlagsMutuallyExclusive("a"))).Build()(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		_, _, err := Command(WithFlagConstraints(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		})).Build()(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func Test_validateFlagConstraints(t *testing.T) {
+	assert.NoError(t, validateFlagConstraints(&cobra.Command{}))
+	assert.Error(t, validateFlagConstraints(&cobra.Command{
+		Annotations: map[string]string{annotationFlagConstraints: "{"},
+	}))
+}
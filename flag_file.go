@@ -0,0 +1,78 @@
+package clix
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// fileFlagValue wraps a string flag value to read it from a file with
+// "@path", or from the standard input with "@-". Literal values starting
+// with "@" are escaped with "@@".
+type fileFlagValue struct {
+	pflag.Value
+	stdin   func() io.Reader
+	maxSize int64
+}
+
+func (v *fileFlagValue) Set(raw string) error {
+	switch {
+	case !strings.HasPrefix(raw, "@"):
+		return v.Value.Set(raw)
+	case strings.HasPrefix(raw, "@@"):
+		return v.Value.Set(raw[1:])
+	case raw == "@-":
+		content, err := readLimited(v.stdin(), v.maxSize)
+		if err != nil {
+			return fmt.Errorf("unable to read value from standard input: %w", err)
+		}
+		return v.Value.Set(content)
+	}
+
+	path := raw[1:]
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open value file: %w", err)
+	}
+	defer file.Close() // nolint: errcheck, gosec
+
+	content, err := readLimited(file, v.maxSize)
+	if err != nil {
+		return fmt.Errorf("unable to read value file %q: %w", path, err)
+	}
+	return v.Value.Set(content)
+}
+
+func readLimited(r io.Reader, maxSize int64) (string, error) {
+	content, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(content)) > maxSize {
+		return "", fmt.Errorf("value is larger than the limit of %d bytes", maxSize)
+	}
+	return string(content), nil
+}
+
+// wrapFileFlagValues makes the string flags of the command
+// and its subcommands accept values from files.
+func wrapFileFlagValues(cmd *cobra.Command, o *flagFileOptions) {
+	stdin := cmd.InOrStdin
+	wrap := func(flag *pflag.Flag) {
+		if _, isWrapped := flag.Value.(*fileFlagValue); isWrapped || flag.Value.Type() != "string" {
+			return
+		}
+		flag.Value = &fileFlagValue{Value: flag.Value, stdin: stdin, maxSize: o.maxSize}
+	}
+
+	cmd.PersistentFlags().VisitAll(wrap)
+	cmd.LocalNonPersistentFlags().VisitAll(wrap)
+	for _, sub := range cmd.Commands() {
+		wrapFileFlagValues(sub, o)
+	}
+}
@@ -0,0 +1,23 @@
+package clix
+
+// defaultMaxValueSize is the default maximum size in bytes
+// of flag values read from files or from the standard input.
+const defaultMaxValueSize = 1 << 20
+
+type flagFileOptions struct {
+	maxSize int64
+}
+
+func defaultFlagFileOptions() *flagFileOptions {
+	return &flagFileOptions{maxSize: defaultMaxValueSize}
+}
+
+// FlagFileOption defines the signature of an option applier.
+type FlagFileOption func(o *flagFileOptions)
+
+// FlagFileWithMaxSize sets the maximum size in bytes of values read
+// from files or from the standard input, 1MiB by default. It also
+// limits the values of Secret flags.
+func FlagFileWithMaxSize(maxSize int64) FlagFileOption {
+	return func(o *flagFileOptions) { o.maxSize = maxSize }
+}
@@ -0,0 +1,79 @@
+package clix
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExecWithFlagFiles(t *testing.T) {
+	type result struct {
+		payload string
+		name    string
+		count   int
+	}
+
+	newCLI := func(res *result) *CLI {
+		return Command(testCommand("app", nil, func(cmd *cobra.Command) error {
+			cmd.PersistentFlags().StringVar(&res.name, "name", "", "")
+			return nil
+		})).SubCommand(testCommand("apply", noopHandler, func(cmd *cobra.Command) error {
+			cmd.Flags().StringVar(&res.payload, "payload", "", "")
+			cmd.Flags().IntVar(&res.count, "count", 0, "")
+			return nil
+		}))
+	}
+
+	path := filepath.Join(t.TempDir(), "payload.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"key":"value"}`+"\n"), 0o600))
+
+	t.Run("values are read from files and stdin", func(t *testing.T) {
+		var res result
+		require.NoError(t, newCLI(&res).Exec(context.Background(),
+			[]string{"apply", "--payload", "@" + path, "--name", "@-", "--count", "2"},
+			ExecWithIO(IO{In: strings.NewReader("from stdin")}), ExecWithFlagFiles(),
+		))
+		assert.Equal(t, `{"key":"value"}`+"\n", res.payload)
+		assert.Equal(t, "from stdin", res.name)
+		assert.Equal(t, 2, res.count)
+	})
+
+	t.Run("literal values are kept", func(t *testing.T) {
+		var res result
+		require.NoError(t, newCLI(&res).Exec(context.Background(),
+			[]string{"apply", "--payload", "@@handle", "--name", "plain"}, ExecWithFlagFiles(),
+		))
+		assert.Equal(t, "@handle", res.payload)
+		assert.Equal(t, "plain", res.name)
+	})
+
+	t.Run("values are not read from files by default", func(t *testing.T) {
+		var res result
+		require.NoError(t, newCLI(&res).Exec(context.Background(), []string{"apply", "--payload", "@" + path}))
+		assert.Equal(t, "@"+path, res.payload)
+	})
+
+	t.Run("too large values", func(t *testing.T) {
+		var res result
+		err := newCLI(&res).Exec(context.Background(), []string{"apply", "--payload", "@" + path},
+			ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}), ExecWithFlagFiles(FlagFileWithMaxSize(4)),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unable to read value file "`+path+`": value is larger than the limit of 4 bytes`)
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		var res result
+		err := newCLI(&res).Exec(context.Background(), []string{"apply", "--payload", "@missing.json"},
+			ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}), ExecWithFlagFiles(),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid argument "@missing.json" for "--payload" flag: unable to open value file`)
+	})
+}
@@ -0,0 +1,83 @@
+package clix
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	annotationHelpSections      = "clix_help_sections"
+	annotationHelpSectionPrefix = "clix_help_"
+	annotationHelpTemplate      = "clix_help_template"
+
+	// helpSectionsTemplate renders the help sections of the command the usage is displayed for.
+	helpSectionsTemplate = "{{clixHelpSections .}}"
+)
+
+func init() {
+	cobra.AddTemplateFunc("clixHelpSections", renderHelpSections)
+}
+
+// addHelpSection adds a titled section to the command usage,
+// displayed right before the list of available subcommands.
+func addHelpSection(cmd *cobra.Command, title string, content string) {
+	key := annotationHelpSectionPrefix + strings.ToLower(title)
+	if _, exists := cmd.Annotations[key]; !exists {
+		titles := cmd.Annotations[annotationHelpSections]
+		if titles != "" {
+			titles += "\n"
+		}
+		setAnnotation(cmd, annotationHelpSections, titles+title)
+	}
+	setAnnotation(cmd, key, content)
+}
+
+func renderHelpSections(cmd *cobra.Command) string {
+	titles := cmd.Annotations[annotationHelpSections]
+	if titles == "" {
+		return ""
+	}
+
+	var sections strings.Builder
+	for _, title := range strings.Split(titles, "\n") {
+		content := cmd.Annotations[annotationHelpSectionPrefix+strings.ToLower(title)]
+		sections.WriteString("\n\n" + title + ":\n" + content)
+	}
+	return sections.String()
+}
+
+// withHelpSections makes the usage template of the command, inherited by its
+// subcommands, render the help sections. Subcommands for which withHelpSections
+// replaced the default template inherit the command one again.
+func withHelpSections(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		if _, replaced := sub.Annotations[annotationHelpTemplate]; replaced {
+			delete(sub.Annotations, annotationHelpTemplate)
+			sub.SetUsageTemplate("")
+		}
+	}
+
+	tmpl := cmd.UsageTemplate()
+	if strings.Contains(tmpl, helpSectionsTemplate) {
+		return
+	}
+	if !cmd.HasParent() && tmpl == new(cobra.Command).UsageTemplate() {
+		setAnnotation(cmd, annotationHelpTemplate, "")
+	}
+
+	const marker = "{{if .HasAvailableSubCommands}}\n\nAvailable Commands:"
+	if i := strings.Index(tmpl, marker); i >= 0 {
+		tmpl = tmpl[:i] + helpSectionsTemplate + tmpl[i:]
+	} else {
+		tmpl += helpSectionsTemplate
+	}
+	cmd.SetUsageTemplate(tmpl)
+}
+
+func setAnnotation(cmd *cobra.Command, key string, value string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[key] = value
+}
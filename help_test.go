@@ -0,0 +1,81 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_addHelpSection(t *testing.T) {
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	addHelpSection(cmd, "First", "  content")
+	addHelpSection(cmd, "Second", "  second content")
+	addHelpSection(cmd, "First", "  updated content")
+
+	assert.Equal(t, "\n\nFirst:\n  updated content\n\nSecond:\n  second content", renderHelpSections(cmd))
+	assert.Empty(t, renderHelpSections(&cobra.Command{}))
+}
+
+func Test_withHelpSections(t *testing.T) {
+	newCommand := func(use string, sections ...string) CommandBuilderFunc {
+		return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			cmd := &cobra.Command{Use: use, Run: func(*cobra.Command, []string) {}}
+			for _, section := range sections {
+				addHelpSection(cmd, section, "  "+use+" "+strings.ToLower(section))
+			}
+			return cmd, ctx, nil
+		}
+	}
+	usage := func(cmd *cobra.Command) string {
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		require.NoError(t, cmd.Usage())
+		return out.String()
+	}
+
+	t.Run("sections are displayed before subcommands", func(t *testing.T) {
+		root, _, err := Command(newCommand("app", "Arguments", "Flag constraints")).
+			SubCommand(Command(newCommand("sub", "Arguments")).SubCommand(newCommand("subsub", "Confirmation")).Build()).
+			Build()(context.Background())
+		require.NoError(t, err)
+
+		assert.Contains(t, usage(root), "\n\nArguments:\n  app arguments\n\nFlag constraints:\n  app flag constraints\n\nAvailable Commands:")
+		assert.Equal(t, 1, strings.Count(root.UsageTemplate(), helpSectionsTemplate))
+
+		sub, _, err := root.Find([]string{"sub"})
+		require.NoError(t, err)
+		assert.Contains(t, usage(sub), "\n\nArguments:\n  sub arguments\n\nAvailable Commands:")
+		assert.NotContains(t, usage(sub), "Flag constraints")
+
+		subsub, _, err := root.Find([]string{"sub", "subsub"})
+		require.NoError(t, err)
+		assert.Contains(t, usage(subsub), "\n\nConfirmation:\n  subsub confirmation")
+	})
+
+	t.Run("subcommands inherit template changes", func(t *testing.T) {
+		root, _, err := Command(newCommand("app")).
+			SubCommand(Command(newCommand("sub", "Arguments")).Build()).
+			Build()(context.Background())
+		require.NoError(t, err)
+
+		root.SetUsageTemplate("custom" + helpSectionsTemplate)
+		sub, _, err := root.Find([]string{"sub"})
+		require.NoError(t, err)
+		assert.Equal(t, "custom\n\nArguments:\n  sub arguments", usage(sub))
+	})
+
+	t.Run("sections are appended to custom templates", func(t *testing.T) {
+		root, _, err := Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			cmd, ctx, err := newCommand("app", "Section")(ctx)
+			cmd.SetUsageTemplate("custom")
+			return cmd, ctx, err
+		}).Build()(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "custom\n\nSection:\n  app section", usage(root))
+	})
+}
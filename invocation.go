@@ -0,0 +1,178 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ctxKeyInvocation ctxKey = "invocation"
+	ctxKeyRawArgs    ctxKey = "raw-args"
+)
+
+// Invocation stores the per-execution information of a command.
+type Invocation struct {
+	// CommandPath is the full path of the executed command, like "app sub subsub".
+	CommandPath string
+	// Args are the positional arguments provided before the double dash.
+	Args []string
+	// DashedArgs are the arguments provided after the double dash.
+	DashedArgs []string
+	// Values are the converted positional arguments, when declared using WithArgs.
+	Values ArgValues
+	// ChangedFlags maps the name of the flags explicitly set to their value.
+	ChangedFlags map[string]string
+	// RawArgs are the unparsed arguments the cli has been executed with.
+	RawArgs []string
+	// IO stores the streams of the command.
+	IO IO
+	// Help displays the command help.
+	Help func()
+
+	flags *pflag.FlagSet
+}
+
+// InvocationFromContext returns the invocation from the context, if present.
+func InvocationFromContext(ctx context.Context) (Invocation, bool) {
+	inv, hasInvocation := ctx.Value(ctxKeyInvocation).(Invocation)
+	return inv, hasInvocation
+}
+
+type (
+	// GetInvocationHandlerFunc returns an invocation handler, providing it's help function.
+	GetInvocationHandlerFunc func(help func()) (InvocationHandler, error)
+	// InvocationHandler abstracts a cli command handler that handles an invocation.
+	InvocationHandler interface {
+		HandleInvocation(ctx context.Context, inv Invocation) error
+	}
+	// InvocationHandlerFunc implements InvocationHandler.
+	InvocationHandlerFunc func(ctx context.Context, inv Invocation) error
+)
+
+// HandleInvocation implements InvocationHandler.
+func (h InvocationHandlerFunc) HandleInvocation(ctx context.Context, inv Invocation) error {
+	return h(ctx, inv)
+}
+
+// AdaptHandler converts a Handler to an InvocationHandler.
+func AdaptHandler(handler Handler) InvocationHandler {
+	return InvocationHandlerFunc(func(ctx context.Context, inv Invocation) error {
+		return handler.Handle(ctx, inv.Args, inv.DashedArgs)
+	})
+}
+
+// ExecInvocationHandler execs the provided invocation handler function, wrapped by the
+// middlewares attached with CLI.Use. The invocation is also available in the handler's
+// context through InvocationFromContext.
+func ExecInvocationHandler(ctx context.Context, getHandler GetInvocationHandlerFunc) func(*cobra.Command, []string) error {
+	return func(c *cobra.Command, args []string) error {
+		// before reaching this point, we want the usage but after that, if we
+		// have an error, we do want to handle when to display it, and when not
+		c.SilenceUsage = true
+
+		help := func() {
+			c.Help() // nolint: errcheck, gosec
+		}
+
+		inv := newInvocation(ctx, c, args, help)
+		return auditInvocation(ctx, &inv, func() error {
+			return execInvocation(ctx, c, &inv, getHandler)
+		})
+	}
+}
+
+// execInvocation prepares the invocation, from prompting missing inputs
+// to confirming destructive commands, and handles it.
+func execInvocation(ctx context.Context, c *cobra.Command, inv *Invocation, getHandler GetInvocationHandlerFunc) error {
+	if err := promptMissingInputs(ctx, c, inv); err != nil {
+		return err
+	}
+	if err := inv.parseDeclaredArgs(c); err != nil {
+		return showUsageOnUsageError(c, err)
+	}
+	if err := validateFlagConstraints(c); err != nil {
+		return showUsageOnUsageError(c, err)
+	}
+	if err := confirmDestructive(ctx, c, *inv); err != nil {
+		return err
+	}
+
+	handler, err := getHandler(inv.Help)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, ctxKeyIO, inv.IO)
+	ctx = context.WithValue(ctx, ctxKeyInvocation, *inv)
+
+	return handleWithTimeout(ctx, c, func(ctx context.Context) error {
+		ctx, end := startStep(ctx, StepHandler)
+		err := applyMiddlewares(ctx, handler).Handle(ctx, inv.Args, inv.DashedArgs)
+		end(err)
+		return err
+	})
+}
+
+func newInvocation(ctx context.Context, c *cobra.Command, args []string, help func()) Invocation {
+	inv := Invocation{
+		CommandPath:  c.CommandPath(),
+		ChangedFlags: make(map[string]string),
+		IO:           ioFromCommand(c),
+		Help:         help,
+		flags:        c.Flags(),
+	}
+
+	inv.Args, inv.DashedArgs = splitArgsAtDash(c, args)
+
+	c.Flags().Visit(func(f *pflag.Flag) {
+		inv.ChangedFlags[f.Name] = f.Value.String()
+	})
+
+	if rawArgs, hasRawArgs := ctx.Value(ctxKeyRawArgs).(*[]string); hasRawArgs {
+		inv.RawArgs = *rawArgs
+	} else if len(os.Args) > 1 {
+		inv.RawArgs = os.Args[1:]
+	}
+
+	return inv
+}
+
+func (inv *Invocation) parseDeclaredArgs(c *cobra.Command) error {
+	declared, isDeclared, err := argsFromCommand(c)
+	if err != nil || !isDeclared {
+		return err
+	}
+
+	inv.Values, err = parseArgs(declared, inv.Args)
+	return err
+}
+
+// showUsageOnUsageError makes sure the command usage is displayed
+// alongside the provided error if it is a usage error.
+func showUsageOnUsageError(c *cobra.Command, err error) error {
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		c.SilenceUsage = false
+	}
+	return err
+}
+
+func splitArgsAtDash(c *cobra.Command, args []string) ([]string, []string) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	argsSeparatedAt := c.ArgsLenAtDash()
+	switch {
+	case argsSeparatedAt == 0 && len(args) > 0:
+		return nil, args
+	case argsSeparatedAt > 0 && len(args[argsSeparatedAt:]) > 0:
+		return args[:argsSeparatedAt], args[argsSeparatedAt:]
+	default:
+		return args, nil
+	}
+}
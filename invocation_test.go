@@ -0,0 +1,96 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InvocationFromContext(t *testing.T) {
+	t.Run("with invocation in context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKeyInvocation, Invocation{CommandPath: "app"})
+		inv, found := InvocationFromContext(ctx)
+		assert.True(t, found)
+		assert.Equal(t, "app", inv.CommandPath)
+	})
+	t.Run("without invocation in context", func(t *testing.T) {
+		_, found := InvocationFromContext(context.Background())
+		assert.False(t, found)
+	})
+}
+
+func Test_AdaptHandler(t *testing.T) {
+	called := false
+	handler := AdaptHandler(HandlerFunc(func(ctx context.Context, args, dashed []string) error {
+		assert.Equal(t, []string{"a"}, args)
+		assert.Equal(t, []string{"b"}, dashed)
+		called = true
+		return nil
+	}))
+	require.NoError(t, handler.HandleInvocation(context.Background(), Invocation{
+		Args:       []string{"a"},
+		DashedArgs: []string{"b"},
+	}))
+	assert.True(t, called)
+}
+
+func Test_ExecInvocationHandler(t *testing.T) {
+	t.Run("invocation is filled", func(t *testing.T) {
+		var flag string
+		called := false
+
+		err := Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}, ctx, nil
+		}).SubCommand(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			cmd := &cobra.Command{
+				Use: "sub",
+				RunE: ExecInvocationHandler(ctx, func(help func()) (InvocationHandler, error) {
+					return InvocationHandlerFunc(func(ctx context.Context, inv Invocation) error {
+						assert.Equal(t, "app sub", inv.CommandPath)
+						assert.Equal(t, []string{"a"}, inv.Args)
+						assert.Equal(t, []string{"b"}, inv.DashedArgs)
+						assert.Equal(t, map[string]string{"flag": "value"}, inv.ChangedFlags)
+						assert.Equal(t, []string{"sub", "a", "--flag", "value", "--", "b"}, inv.RawArgs)
+						assert.NotNil(t, inv.IO.Out)
+						assert.NotNil(t, inv.Help)
+
+						fromCtx, found := InvocationFromContext(ctx)
+						assert.True(t, found)
+						assert.Equal(t, inv.CommandPath, fromCtx.CommandPath)
+						called = true
+						return nil
+					}), nil
+				}),
+			}
+			cmd.Flags().StringVar(&flag, "flag", "", "")
+			cmd.Flags().String("unchanged", "", "")
+			return cmd, ctx, nil
+		}).Exec(context.Background(), []string{"sub", "a", "--flag", "value", "--", "b"})
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("handler getter failed", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "sub", SilenceErrors: true}
+		cmd.RunE = ExecInvocationHandler(context.Background(), func(func()) (InvocationHandler, error) {
+			return nil, errors.New("boum")
+		})
+		cmd.SetArgs([]string{})
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("handler failed", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "sub", SilenceErrors: true}
+		cmd.RunE = ExecInvocationHandler(context.Background(), func(func()) (InvocationHandler, error) {
+			return InvocationHandlerFunc(func(context.Context, Invocation) error {
+				return errors.New("boum")
+			}), nil
+		})
+		cmd.SetArgs([]string{})
+		require.Error(t, cmd.Execute())
+	})
+}
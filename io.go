@@ -0,0 +1,58 @@
+package clix
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const ctxKeyIO ctxKey = "io"
+
+// IO stores the streams a command reads from and writes to.
+type IO struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// IOFromContext returns the command streams from the context,
+// falling back to the standard streams if not present.
+func IOFromContext(ctx context.Context) IO {
+	if streams, hasIO := ctx.Value(ctxKeyIO).(IO); hasIO {
+		return streams
+	}
+	return IO{In: os.Stdin, Out: os.Stdout, Err: os.Stderr}
+}
+
+func ioFromCommand(cmd *cobra.Command) IO {
+	return IO{In: cmd.InOrStdin(), Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+}
+
+func (s IO) applyToCommand(cmd *cobra.Command) {
+	if s.In != nil {
+		cmd.SetIn(s.In)
+	}
+	if s.Out != nil {
+		cmd.SetOut(s.Out)
+	}
+	if s.Err != nil {
+		cmd.SetErr(s.Err)
+	}
+}
+
+// IsInTerminal returns whenever the input stream is a terminal.
+func (s IO) IsInTerminal() bool { return isTerminal(s.In) }
+
+// IsOutTerminal returns whenever the output stream is a terminal.
+func (s IO) IsOutTerminal() bool { return isTerminal(s.Out) }
+
+// IsErrTerminal returns whenever the error stream is a terminal.
+func (s IO) IsErrTerminal() bool { return isTerminal(s.Err) }
+
+func isTerminal(stream interface{}) bool {
+	f, isFile := stream.(interface{ Fd() uintptr })
+	return isFile && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,47 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IOFromContext(t *testing.T) {
+	t.Run("with io in context", func(t *testing.T) {
+		streams := IO{In: strings.NewReader(""), Out: new(bytes.Buffer), Err: new(bytes.Buffer)}
+		ctx := context.WithValue(context.Background(), ctxKeyIO, streams)
+		assert.Equal(t, streams, IOFromContext(ctx))
+	})
+	t.Run("without io in context", func(t *testing.T) {
+		assert.Equal(t, IO{In: os.Stdin, Out: os.Stdout, Err: os.Stderr}, IOFromContext(context.Background()))
+	})
+}
+
+func TestIO_applyToCommand(t *testing.T) {
+	var (
+		cmd cobra.Command
+		in  = strings.NewReader("")
+		out bytes.Buffer
+	)
+
+	IO{In: in, Out: &out}.applyToCommand(&cmd)
+	assert.Equal(t, IO{In: in, Out: &out, Err: os.Stderr}, ioFromCommand(&cmd))
+}
+
+func TestIO_IsTerminal(t *testing.T) {
+	streams := IO{In: strings.NewReader(""), Out: new(bytes.Buffer), Err: new(bytes.Buffer)}
+	assert.False(t, streams.IsInTerminal())
+	assert.False(t, streams.IsOutTerminal())
+	assert.False(t, streams.IsErrTerminal())
+
+	devNull, err := os.Open(os.DevNull)
+	if assert.NoError(t, err) {
+		defer devNull.Close() // nolint: errcheck, gosec
+		assert.False(t, IO{In: devNull}.IsInTerminal())
+	}
+}
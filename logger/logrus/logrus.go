@@ -0,0 +1,26 @@
+// Package logrus provides a clix logger backend based on sirupsen/logrus.
+package logrus
+
+import (
+	"io"
+
+	"github.com/krostar/logger"
+	"github.com/krostar/logger/logrus"
+
+	"github.com/krostar/clix"
+)
+
+// Create creates a logrus logger configured from the logger configuration,
+// writing logs to the provided writer.
+func Create(cfg logger.Config, w io.Writer, opts ...logrus.Option) (logger.Logger, error) {
+	cfg.Output = "" // output is handled by the provided writer
+	return logrus.New(append([]logrus.Option{logrus.WithConfig(cfg), logrus.WithOutput(w)}, opts...)...)
+}
+
+// Option sets logrus as the logger backend of clix.WithLogger.
+// Provided options are applied after the logger configuration.
+func Option(opts ...logrus.Option) clix.LoggerCommandOption {
+	return clix.LoggerWithCreateWriterFunc(func(cfg logger.Config, w io.Writer) (logger.Logger, error) {
+		return Create(cfg, w, opts...)
+	})
+}
@@ -0,0 +1,49 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/krostar/logger/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/krostar/clix"
+)
+
+func Test_Create(t *testing.T) {
+	var cfg logger.Config
+	cfg.SetDefault()
+	cfg.Formatter = "json"
+
+	var buf bytes.Buffer
+	log, err := Create(cfg, &buf)
+	require.NoError(t, err)
+	assert.IsType(t, &logrus.Logrus{}, log)
+
+	log.Info("hello")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "hello", entry["msg"])
+}
+
+func Test_Option(t *testing.T) {
+	var buf bytes.Buffer
+	err := clix.Command(clix.WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{
+			RunE: clix.ExecHandler(ctx, func(func()) (clix.Handler, error) {
+				return clix.HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					assert.IsType(t, &logrus.Logrus{}, clix.LoggerFromContext(ctx))
+					clix.LoggerFromContext(ctx).Info("hello")
+					return nil
+				}), nil
+			}),
+		}, ctx, nil
+	}, Option(logrus.WithOutput(&buf)))).Exec(context.Background(), []string{})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "hello")
+}
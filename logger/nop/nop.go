@@ -0,0 +1,16 @@
+// Package nop provides a clix logger backend that discards every logs.
+package nop
+
+import (
+	"io"
+
+	"github.com/krostar/logger"
+
+	"github.com/krostar/clix"
+)
+
+// Create creates a logger that discards every logs.
+func Create(logger.Config, io.Writer) (logger.Logger, error) { return &logger.Noop{}, nil }
+
+// Option sets the no-operation logger as the logger backend of clix.WithLogger.
+func Option() clix.LoggerCommandOption { return clix.LoggerWithCreateWriterFunc(Create) }
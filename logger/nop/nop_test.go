@@ -0,0 +1,33 @@
+package nop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/krostar/clix"
+)
+
+func Test_Create(t *testing.T) {
+	log, err := Create(logger.Config{}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &logger.Noop{}, log)
+}
+
+func Test_Option(t *testing.T) {
+	err := clix.Command(clix.WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{
+			RunE: clix.ExecHandler(ctx, func(func()) (clix.Handler, error) {
+				return clix.HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					assert.IsType(t, logger.Noop{}, clix.LoggerFromContext(ctx))
+					return nil
+				}), nil
+			}),
+		}, ctx, nil
+	}, Option())).Exec(context.Background(), []string{})
+	require.NoError(t, err)
+}
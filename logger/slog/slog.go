@@ -0,0 +1,110 @@
+// Package slog provides a clix logger backend based on the standard library log/slog.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/krostar/logger"
+
+	"github.com/krostar/clix"
+)
+
+// Slog implements the logger.Logger interface.
+type Slog struct {
+	log   *slog.Logger
+	level *slog.LevelVar
+}
+
+// New wraps a slog logger, whose handler is expected to use the provided level.
+func New(log *slog.Logger, level *slog.LevelVar) *Slog {
+	return &Slog{log: log, level: level}
+}
+
+// Create creates a slog logger configured from the logger configuration,
+// writing logs to the provided writer.
+func Create(cfg logger.Config, w io.Writer) (logger.Logger, error) {
+	lvl, err := logger.ParseLevel(cfg.Verbosity)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse level %q: %w", cfg.Verbosity, err)
+	}
+
+	level := new(slog.LevelVar)
+	handler, err := clix.NewSlogHandler(cfg, w, &slog.HandlerOptions{Level: level})
+	if err != nil {
+		return nil, err
+	}
+
+	log := New(slog.New(handler), level)
+	if err := log.SetLevel(lvl); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// Option sets slog as the logger backend of clix.WithLogger.
+func Option() clix.LoggerCommandOption { return clix.LoggerWithCreateWriterFunc(Create) }
+
+// SetLevel applies a new level to a logger instance.
+func (l *Slog) SetLevel(level logger.Level) error {
+	lvl, err := clix.SlogLevel(level)
+	if err != nil {
+		return fmt.Errorf("unable to convert level: %w", err)
+	}
+	l.level.Set(lvl)
+	return nil
+}
+
+// Debug implements Logger.Debug for slog logger.
+func (l *Slog) Debug(args ...interface{}) { l.log.Debug(fmt.Sprint(args...)) }
+
+// Debugf implements Logger.Debugf for slog logger.
+func (l *Slog) Debugf(format string, args ...interface{}) { l.logf(slog.LevelDebug, format, args) }
+
+// Info implements Logger.Info for slog logger.
+func (l *Slog) Info(args ...interface{}) { l.log.Info(fmt.Sprint(args...)) }
+
+// Infof implements Logger.Infof for slog logger.
+func (l *Slog) Infof(format string, args ...interface{}) { l.logf(slog.LevelInfo, format, args) }
+
+// Warn implements Logger.Warn for slog logger.
+func (l *Slog) Warn(args ...interface{}) { l.log.Warn(fmt.Sprint(args...)) }
+
+// Warnf implements Logger.Warnf for slog logger.
+func (l *Slog) Warnf(format string, args ...interface{}) { l.logf(slog.LevelWarn, format, args) }
+
+// Error implements Logger.Error for slog logger.
+func (l *Slog) Error(args ...interface{}) { l.log.Error(fmt.Sprint(args...)) }
+
+// Errorf implements Logger.Errorf for slog logger.
+func (l *Slog) Errorf(format string, args ...interface{}) { l.logf(slog.LevelError, format, args) }
+
+func (l *Slog) logf(level slog.Level, format string, args []interface{}) {
+	if l.log.Enabled(context.Background(), level) {
+		l.log.Log(context.Background(), level, fmt.Sprintf(format, args...))
+	}
+}
+
+// WithField implements Logger.WithField for slog logger.
+func (l *Slog) WithField(key string, value interface{}) logger.Logger {
+	return &Slog{log: l.log.With(key, value), level: l.level}
+}
+
+// WithFields implements Logger.WithFields for slog logger.
+func (l *Slog) WithFields(fields map[string]interface{}) logger.Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for key, value := range fields {
+		args = append(args, key, value)
+	}
+	return &Slog{log: l.log.With(args...), level: l.level}
+}
+
+// WithError implements Logger.WithError for slog logger.
+func (l *Slog) WithError(err error) logger.Logger {
+	if err != nil {
+		return l.WithField(logger.FieldErrorKey, err.Error())
+	}
+	return l
+}
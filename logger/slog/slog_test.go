@@ -0,0 +1,116 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/krostar/clix"
+)
+
+func newBufferedSlog(buf *bytes.Buffer) *Slog {
+	level := new(slog.LevelVar)
+	return New(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level})), level)
+}
+
+func readEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		require.NoError(t, decoder.Decode(&entry))
+		delete(entry, "time")
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func Test_Create(t *testing.T) {
+	t.Run("logs are written to the writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, err := Create(logger.Config{Verbosity: "warn", Formatter: "json"}, &buf)
+		require.NoError(t, err)
+		log.Info("hidden")
+		log.WithField("key", "value").Warn("displayed")
+
+		entries := readEntries(t, &buf)
+		assert.Equal(t, []map[string]interface{}{{"level": "WARN", "msg": "displayed", "key": "value"}}, entries)
+	})
+
+	t.Run("console formatter", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, err := Create(logger.Config{Formatter: "console"}, &buf)
+		require.NoError(t, err)
+		log.Info("hello")
+		assert.Contains(t, buf.String(), "msg=hello")
+	})
+
+	t.Run("invalid configurations", func(t *testing.T) {
+		_, err := Create(logger.Config{Verbosity: "boum", Formatter: "json"}, ioutil.Discard)
+		assert.Error(t, err)
+		_, err = Create(logger.Config{Formatter: "boum"}, ioutil.Discard)
+		assert.Error(t, err)
+	})
+}
+
+func TestSlog_levels(t *testing.T) {
+	var buf bytes.Buffer
+	log := newBufferedSlog(&buf)
+
+	require.NoError(t, log.SetLevel(logger.LevelDebug))
+	log.Debug("a")
+	log.Debugf("%s", "b")
+	log.Info("c")
+	log.Infof("%s", "d")
+	log.Warn("e")
+	log.Warnf("%s", "f")
+	log.Error("g")
+	log.Errorf("%s", "h")
+
+	require.NoError(t, log.SetLevel(logger.LevelQuiet))
+	log.Errorf("%s", "hidden")
+
+	assert.Error(t, log.SetLevel(logger.Level(42)))
+
+	var messages []string
+	for _, entry := range readEntries(t, &buf) {
+		messages = append(messages, entry["msg"].(string))
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h"}, messages)
+}
+
+func TestSlog_fields(t *testing.T) {
+	var buf bytes.Buffer
+	log := newBufferedSlog(&buf)
+
+	log.WithFields(map[string]interface{}{"a": "b"}).WithError(errors.New("boum")).WithError(nil).Info("msg")
+	assert.Equal(t, []map[string]interface{}{{
+		"level": "INFO",
+		"msg":   "msg",
+		"a":     "b",
+		"error": "boum",
+	}}, readEntries(t, &buf))
+}
+
+func Test_Option(t *testing.T) {
+	err := clix.Command(clix.WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{
+			RunE: clix.ExecHandler(ctx, func(func()) (clix.Handler, error) {
+				return clix.HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					assert.IsType(t, &Slog{}, clix.LoggerFromContext(ctx))
+					return nil
+				}), nil
+			}),
+		}, ctx, nil
+	}, Option())).Exec(context.Background(), []string{"-v", "error"})
+	require.NoError(t, err)
+}
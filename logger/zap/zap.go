@@ -0,0 +1,119 @@
+// Package zap provides a clix logger backend based on uber-go/zap.
+package zap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/krostar/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/krostar/clix"
+)
+
+// Zap implements the logger.Logger interface.
+type Zap struct {
+	*zap.SugaredLogger
+	level zap.AtomicLevel
+}
+
+// Create creates a zap logger configured from the logger configuration,
+// writing logs to the provided writer.
+func Create(cfg logger.Config, w io.Writer, opts ...zap.Option) (logger.Logger, error) {
+	lvl, err := logger.ParseLevel(cfg.Verbosity)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse level %q: %w", cfg.Verbosity, err)
+	}
+
+	encoderConfig := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LineEnding:     zapcore.DefaultLineEnding,
+		LevelKey:       "lvl",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		TimeKey:        "time",
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		CallerKey:      "caller",
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	var encoder zapcore.Encoder
+	switch cfg.Formatter {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console":
+		if cfg.WithColor {
+			encoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("unknown formatter %s", cfg.Formatter)
+	}
+
+	log := &Zap{level: zap.NewAtomicLevel()}
+	if err := log.SetLevel(lvl); err != nil {
+		return nil, err
+	}
+	log.SugaredLogger = zap.New(zapcore.NewCore(encoder, zapcore.AddSync(w), log.level), opts...).Sugar()
+
+	return log, nil
+}
+
+// Option sets zap as the logger backend of clix.WithLogger.
+// Provided options are applied to the zap logger.
+func Option(opts ...zap.Option) clix.LoggerCommandOption {
+	return clix.LoggerWithCreateWriterFunc(func(cfg logger.Config, w io.Writer) (logger.Logger, error) {
+		return Create(cfg, w, opts...)
+	})
+}
+
+func convertLevel(level logger.Level) (zapcore.Level, error) {
+	switch level {
+	case logger.LevelDebug:
+		return zapcore.DebugLevel, nil
+	case logger.LevelInfo:
+		return zapcore.InfoLevel, nil
+	case logger.LevelWarn:
+		return zapcore.WarnLevel, nil
+	case logger.LevelError:
+		return zapcore.ErrorLevel, nil
+	case logger.LevelQuiet:
+		return zapcore.FatalLevel + 1, nil
+	default:
+		return 0, errors.New("level conversion to zap level impossible")
+	}
+}
+
+// SetLevel applies a new level to a logger instance.
+func (l *Zap) SetLevel(level logger.Level) error {
+	lvl, err := convertLevel(level)
+	if err != nil {
+		return fmt.Errorf("unable to convert level: %w", err)
+	}
+	l.level.SetLevel(lvl)
+	return nil
+}
+
+// WithField implements Logger.WithField for zap logger.
+func (l *Zap) WithField(key string, value interface{}) logger.Logger {
+	return &Zap{SugaredLogger: l.With(key, value), level: l.level}
+}
+
+// WithFields implements Logger.WithFields for zap logger.
+func (l *Zap) WithFields(fields map[string]interface{}) logger.Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for key, value := range fields {
+		args = append(args, key, value)
+	}
+	return &Zap{SugaredLogger: l.With(args...), level: l.level}
+}
+
+// WithError implements Logger.WithError for zap logger.
+func (l *Zap) WithError(err error) logger.Logger {
+	if err != nil {
+		return l.WithField(logger.FieldErrorKey, err.Error())
+	}
+	return l
+}
@@ -0,0 +1,84 @@
+package zap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/krostar/clix"
+)
+
+func Test_Create(t *testing.T) {
+	t.Run("logs are written to the writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, err := Create(logger.Config{Verbosity: "warn", Formatter: "json"}, &buf)
+		require.NoError(t, err)
+		assert.IsType(t, &Zap{}, log)
+
+		log.Info("hidden")
+		log.WithFields(map[string]interface{}{"a": "b"}).WithError(errors.New("boum")).WithError(nil).Warn("displayed")
+
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		delete(entry, "time")
+		assert.Equal(t, map[string]interface{}{"lvl": "warn", "msg": "displayed", "a": "b", "error": "boum"}, entry)
+	})
+
+	t.Run("console formatter", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, err := Create(logger.Config{Formatter: "console", WithColor: true}, &buf)
+		require.NoError(t, err)
+		log.Info("hello")
+		assert.Contains(t, buf.String(), "hello")
+	})
+
+	t.Run("quiet level", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, err := Create(logger.Config{Verbosity: "quiet", Formatter: "json"}, &buf)
+		require.NoError(t, err)
+		log.Error("hidden")
+		assert.Empty(t, buf.String())
+		assert.Error(t, log.SetLevel(logger.Level(42)))
+	})
+
+	t.Run("invalid configurations", func(t *testing.T) {
+		_, err := Create(logger.Config{Verbosity: "boum", Formatter: "json"}, ioutil.Discard)
+		assert.Error(t, err)
+		_, err = Create(logger.Config{Formatter: "boum"}, ioutil.Discard)
+		assert.Error(t, err)
+	})
+}
+
+func Test_Option(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clix-zap")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck, gosec
+	path := filepath.Join(dir, "logs")
+
+	err = clix.Command(clix.WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{
+			RunE: clix.ExecHandler(ctx, func(func()) (clix.Handler, error) {
+				return clix.HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					assert.IsType(t, &Zap{}, clix.LoggerFromContext(ctx))
+					clix.LoggerFromContext(ctx).Info("hello")
+					return nil
+				}), nil
+			}),
+		}, ctx, nil
+	}, Option())).Exec(context.Background(), []string{"--log-output", path})
+	require.NoError(t, err)
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "hello")
+}
@@ -2,7 +2,13 @@ package clix
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"strings"
 
 	"github.com/krostar/logger"
 	"github.com/spf13/cobra"
@@ -20,49 +26,173 @@ func LoggerFromContext(ctx context.Context) logger.Logger {
 	return nil
 }
 
+// ErrLoggerBackendMissing is returned by WithLogger when no logger backend is configured.
+var ErrLoggerBackendMissing = errors.New("no logger backend configured, use LoggerWithCreateFunc or a logger sub-package option")
+
 // WithLogger adds to an existing command log flags, and config requirements.
+// The logger backend has to be provided, using LoggerWithCreateFunc or the
+// option of one of the logger sub-packages. Logrus is no longer the default
+// backend: to keep using it, set the option of the logger/logrus sub-package.
 func WithLogger(cbf CommandBuilderFunc, opts ...LoggerCommandOption) CommandBuilderFunc {
 	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		o := defaultLoggerCommandOptions()
+		for _, opt := range opts {
+			opt(o)
+		}
+		if o.createLoggerFunc == nil {
+			return nil, nil, ErrLoggerBackendMissing
+		}
+
 		log := new(logger.Logger)
 		ctx = context.WithValue(ctx, ctxKeyLogger, log)
 
+		var slogLog *slog.Logger
+		if o.slog != nil {
+			slogLog = new(slog.Logger)
+			ctx = context.WithValue(ctx, ctxKeySlog, slogLog)
+		}
+
 		cmd, ctx, err := cbf(ctx)
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to build root command: %w", err)
 		}
-
-		o := defaultLoggerCommandOptions()
-		for _, opt := range opts {
-			opt(o)
+		app, _ := AppFromContext(ctx)
+		if o.appName != "" && app.Name == "" {
+			cmd.Use = o.appName
+		}
+		if app.Version != "" {
+			o.appVersion = app.Version
+		} else if o.appVersion != "" {
+			cmd.Version = o.appVersion
 		}
-		o.applyToCommand(cmd)
 
 		var cfg logger.Config
 		cfg.SetDefault()
+		cfg.Output = LogOutputStderr
+
+		levelOverrides := make(map[string]string)
 
 		o.setPersistentFlags(cmd.PersistentFlags(), &cfg)
-		cmd.PersistentPreRunE = loggerPreRunInit(o.createLoggerFunc, &cfg, log)
+		cmd.PersistentFlags().StringToStringVar(&levelOverrides,
+			"log-level-for", nil,
+			"verbosity of logs for specific subcommands, like sub.subsub=debug",
+		)
+		appendPersistentPreRunE(cmd, loggerPreRunInit(ctx, o, &cfg, &levelOverrides, log, slogLog))
 
 		return cmd, ctx, nil
 	}
 }
 
 func loggerPreRunInit(
-	createLogger func(cfg logger.Config) (logger.Logger, error),
+	ctx context.Context,
+	o *loggerCommandOptions,
 	cfg *logger.Config,
+	levelOverrides *map[string]string,
 	logPtr *logger.Logger,
+	slogPtr *slog.Logger,
 ) func(cmd *cobra.Command, args []string) error {
-	return func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		_, end := startStep(ctx, StepLoggerInit)
+		defer func() { end(err) }()
+
+		commandKey := loggerCommandKey(cmd)
+
+		cfg := *cfg
+		verbosity, overridden := levelOverrideFor(commandKey, *levelOverrides)
+		if overridden {
+			cfg.Verbosity = verbosity
+		}
+
 		if err := cfg.Validate(); err != nil {
 			return fmt.Errorf("logger config is invalid: %w", err)
 		}
 
-		var err error
-		*logPtr, err = createLogger(*cfg)
-		if err != nil {
-			return fmt.Errorf("unable to create logger: %w", err)
+		// backends set with LoggerWithCreateFunc open the output on their own
+		var w io.Writer
+		if !o.createLoggerOpensOutput || slogPtr != nil {
+			if o.createLoggerOpensOutput && isLogOutputFile(cfg.Output) {
+				return fmt.Errorf("file log output %q can't be opened by both slog and the backend, "+
+					"use LoggerWithCreateWriterFunc", cfg.Output)
+			}
+			if w, err = openLogOutput(cfg.Output, o.outputRotation, ioFromCommand(cmd)); err != nil {
+				return fmt.Errorf("logger config is invalid: %w", err)
+			}
+			if file, isFile := w.(*rotatingFile); isFile {
+				closeAfterExec(ctx, file)
+			}
+		}
+
+		// not every backend supports the quiet level, and no backend is needed to log nothing
+		var log logger.Logger = &logger.Noop{}
+		if lvl, _ := logger.ParseLevel(cfg.Verbosity); lvl != logger.LevelQuiet {
+			if log, err = o.createLoggerFunc(cfg, w); err != nil {
+				return fmt.Errorf("unable to create logger: %w", err)
+			}
+		}
+
+		fields := map[string]interface{}{
+			"command":       commandKey,
+			"invocation_id": newInvocationID(),
+		}
+		if o.appVersion != "" {
+			fields["version"] = o.appVersion
+		}
+		*logPtr = log.WithFields(fields)
+
+		if slogPtr != nil {
+			log, err := newSlog(cfg, w, o.slog, overridden)
+			if err != nil {
+				return fmt.Errorf("unable to create slog logger: %w", err)
+			}
+			for key, value := range fields {
+				log = log.With(key, value)
+			}
+			*slogPtr = *log
 		}
 
 		return nil
 	}
 }
+
+// loggerCommandKey returns the path of the command, without the root command,
+// like "sub.subsub". The root command key is its name.
+func loggerCommandKey(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return cmd.Name()
+	}
+
+	var path []string
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		path = append([]string{c.Name()}, path...)
+	}
+	return strings.Join(path, ".")
+}
+
+// levelOverrideFor returns the verbosity of the most specific
+// override matching the command or one of its parents.
+func levelOverrideFor(commandKey string, levelOverrides map[string]string) (string, bool) {
+	var (
+		verbosity string
+		matched   string
+		found     bool
+	)
+
+	for key, value := range levelOverrides {
+		if key != commandKey && !strings.HasPrefix(commandKey, key+".") {
+			continue
+		}
+		if !found || len(key) > len(matched) {
+			verbosity, matched, found = value, key, true
+		}
+	}
+
+	return verbosity, found
+}
+
+func newInvocationID() string {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(raw)
+}
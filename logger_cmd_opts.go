@@ -1,43 +1,37 @@
 package clix
 
 import (
+	"io"
+
 	"github.com/krostar/logger"
-	"github.com/krostar/logger/logrus"
-	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type loggerCommandOptions struct {
-	appName            string
-	appVersion         string
-	createLoggerFunc   func(cfg logger.Config) (logger.Logger, error)
-	setPersistentFlags func(flags *pflag.FlagSet, cfg *logger.Config)
-}
-
-func (c *loggerCommandOptions) applyToCommand(cmd *cobra.Command) {
-	if c.appName != "" {
-		cmd.Use = c.appName
-	}
-	if c.appVersion != "" {
-		cmd.Version = c.appVersion
-	}
+	appName          string
+	appVersion       string
+	createLoggerFunc func(cfg logger.Config, w io.Writer) (logger.Logger, error)
+	// createLoggerOpensOutput is set when the backend opens the configured output itself
+	createLoggerOpensOutput bool
+	setPersistentFlags      func(flags *pflag.FlagSet, cfg *logger.Config)
+	outputRotation          LogOutputRotation
+	slog                    *slogOptions
 }
 
 func defaultLoggerCommandOptions() *loggerCommandOptions {
 	return &loggerCommandOptions{
-		appName:    "cli-app",
-		appVersion: "dev",
-		createLoggerFunc: func(cfg logger.Config) (logger.Logger, error) {
-			return logrus.New(logrus.WithConfig(cfg))
-		},
 		setPersistentFlags: func(flags *pflag.FlagSet, cfg *logger.Config) {
 			flags.StringVarP(&cfg.Verbosity,
 				"log-verbosity", "v", cfg.Verbosity,
-				"verbosity of logs printed to the standard output",
+				"verbosity of logs printed to the log output",
 			)
 			flags.StringVarP(&cfg.Formatter,
 				"log-format", "f", cfg.Formatter,
-				"format to print logs to standard output with",
+				"format to print logs to the log output with",
+			)
+			flags.StringVar(&cfg.Output,
+				"log-output", cfg.Output,
+				"where to print logs to, one of stderr|stdout|<file path>",
 			)
 		},
 	}
@@ -46,12 +40,18 @@ func defaultLoggerCommandOptions() *loggerCommandOptions {
 // LoggerCommandOption defines the signature of an option applier.
 type LoggerCommandOption func(o *loggerCommandOptions)
 
-// LoggerWithAppName sets the root command app name.
+// LoggerWithAppName sets the root command app name,
+// when no application name is defined with WithApp.
+//
+// Deprecated: the logger does not own the app identity anymore, use WithApp and AppWithName.
 func LoggerWithAppName(appName string) LoggerCommandOption {
 	return func(o *loggerCommandOptions) { o.appName = appName }
 }
 
-// LoggerWithVersion sets the root command app version.
+// LoggerWithVersion sets the root command app version, and the version added
+// to every logs, when no application version is defined with WithApp.
+//
+// Deprecated: use WithApp and AppWithVersion.
 func LoggerWithVersion(version string) LoggerCommandOption {
 	return func(o *loggerCommandOptions) { o.appVersion = version }
 }
@@ -61,7 +61,38 @@ func LoggerWithPersistentFlagsFunc(fct func(flags *pflag.FlagSet, cfg *logger.Co
 	return func(o *loggerCommandOptions) { o.setPersistentFlags = fct }
 }
 
-// LoggerWithCreateFunc sets the logger creation function.
+// LoggerWithCreateFunc sets the logger creation function. A backend is required by
+// WithLogger; ready-made backends are available in the logger sub-packages.
+// The created logger is responsible of writing to the configured output,
+// use LoggerWithCreateWriterFunc to benefit from the log output handling.
 func LoggerWithCreateFunc(fct func(log logger.Config) (logger.Logger, error)) LoggerCommandOption {
-	return func(o *loggerCommandOptions) { o.createLoggerFunc = fct }
+	return func(o *loggerCommandOptions) {
+		o.createLoggerFunc = func(cfg logger.Config, _ io.Writer) (logger.Logger, error) { return fct(cfg) }
+		o.createLoggerOpensOutput = true
+	}
+}
+
+// LoggerWithCreateWriterFunc sets the logger creation function,
+// providing the writer the logs should be written to.
+func LoggerWithCreateWriterFunc(fct func(log logger.Config, w io.Writer) (logger.Logger, error)) LoggerCommandOption {
+	return func(o *loggerCommandOptions) {
+		o.createLoggerFunc = fct
+		o.createLoggerOpensOutput = false
+	}
+}
+
+// LoggerWithOutputRotation sets the rotation policy of file log outputs.
+func LoggerWithOutputRotation(rotation LogOutputRotation) LoggerCommandOption {
+	return func(o *loggerCommandOptions) { o.outputRotation = rotation }
+}
+
+// LoggerWithSlog additionally creates a standard library structured logger from
+// the same logger configuration, available through SlogFromContext.
+func LoggerWithSlog(opts ...SlogOption) LoggerCommandOption {
+	return func(o *loggerCommandOptions) {
+		o.slog = new(slogOptions)
+		for _, opt := range opts {
+			opt(o.slog)
+		}
+	}
 }
@@ -1,11 +1,11 @@
 package clix
 
 import (
+	"io"
+	"log/slog"
 	"testing"
 
 	"github.com/krostar/logger"
-	"github.com/krostar/logger/logrus"
-	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,25 +13,21 @@ import (
 
 func Test_defaultLoggerCommandOptions(t *testing.T) {
 	o := defaultLoggerCommandOptions()
-	assert.Equal(t, "cli-app", o.appName)
-	assert.Equal(t, "dev", o.appVersion)
+	assert.Empty(t, o.appVersion)
 
-	t.Run("logrus logger by default", func(t *testing.T) {
-		var cfg logger.Config
-		cfg.SetDefault()
-		log, err := o.createLoggerFunc(cfg)
-		require.NoError(t, err)
-		assert.IsType(t, &logrus.Logrus{}, log)
+	t.Run("no logger backend by default", func(t *testing.T) {
+		assert.Nil(t, o.createLoggerFunc)
 	})
 
 	t.Run("long flags should set the config", func(t *testing.T) {
 		var cfg logger.Config
 		flags := pflag.NewFlagSet("", pflag.ContinueOnError)
 		o.setPersistentFlags(flags, &cfg)
-		err := flags.Parse([]string{"--log-verbosity", "error", "--log-format", "json"})
+		err := flags.Parse([]string{"--log-verbosity", "error", "--log-format", "json", "--log-output", "stdout"})
 		require.NoError(t, err)
 		assert.Equal(t, "error", cfg.Verbosity)
 		assert.Equal(t, "json", cfg.Formatter)
+		assert.Equal(t, "stdout", cfg.Output)
 	})
 
 	t.Run("short flags should set the config", func(t *testing.T) {
@@ -45,24 +41,6 @@ func Test_defaultLoggerCommandOptions(t *testing.T) {
 	})
 }
 
-func TestLoggerCommandOptions_applyToCommand(t *testing.T) {
-	var (
-		cmd cobra.Command
-		o   loggerCommandOptions
-	)
-
-	o.applyToCommand(&cmd)
-	assert.Equal(t, cobra.Command{}, cmd)
-
-	o.appName = "app"
-	o.appVersion = "version"
-	o.applyToCommand(&cmd)
-	assert.Equal(t, cobra.Command{
-		Use:     "app",
-		Version: "version",
-	}, cmd)
-}
-
 func Test_LoggerWithAppName(t *testing.T) {
 	var o loggerCommandOptions
 	LoggerWithAppName("go-app")(&o)
@@ -77,12 +55,35 @@ func Test_LoggerWithVersion(t *testing.T) {
 
 func Test_LoggerWithLoggerCreateFunc(t *testing.T) {
 	var o loggerCommandOptions
-	LoggerWithCreateFunc(func(logger.Config) (logger.Logger, error) { return nil, nil })(&o)
+	LoggerWithCreateFunc(func(logger.Config) (logger.Logger, error) { return &logger.Noop{}, nil })(&o)
+	require.NotNil(t, o.createLoggerFunc)
+	log, err := o.createLoggerFunc(logger.Config{}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &logger.Noop{}, log)
+}
+
+func Test_LoggerWithLoggerCreateWriterFunc(t *testing.T) {
+	var o loggerCommandOptions
+	LoggerWithCreateWriterFunc(func(logger.Config, io.Writer) (logger.Logger, error) { return nil, nil })(&o)
 	assert.NotNil(t, o.createLoggerFunc)
 }
 
+func Test_LoggerWithOutputRotation(t *testing.T) {
+	var o loggerCommandOptions
+	LoggerWithOutputRotation(LogOutputRotation{MaxSize: 42})(&o)
+	assert.Equal(t, LogOutputRotation{MaxSize: 42}, o.outputRotation)
+}
+
 func Test_LoggerWithPersistentFlagsFunc(t *testing.T) {
 	var o loggerCommandOptions
 	LoggerWithPersistentFlagsFunc(func(flags *pflag.FlagSet, cfg *logger.Config) {})(&o)
 	assert.NotNil(t, o.setPersistentFlags)
 }
+
+func Test_LoggerWithSlog(t *testing.T) {
+	var o loggerCommandOptions
+	LoggerWithSlog(SlogWithSource(), SlogWithLevel(slog.LevelDebug))(&o)
+	require.NotNil(t, o.slog)
+	assert.True(t, o.slog.addSource)
+	assert.Equal(t, slog.LevelDebug, o.slog.level)
+}
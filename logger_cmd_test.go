@@ -1,14 +1,20 @@
 package clix
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/krostar/logger"
+	logruslogger "github.com/krostar/logger/logrus"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -31,10 +37,11 @@ func Test_LoggerFromContext(t *testing.T) {
 }
 
 func Test_WithLogger(t *testing.T) {
-	t.Run("default should be enough to log an info", func(t *testing.T) {
+	t.Run("logrus backend should be enough to log an info", func(t *testing.T) {
 		outputRaw, err := logger.CaptureOutput(func() { // capture everything printed to std{out,err}
 			err := Command(WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
 				return &cobra.Command{
+					Use: "cli-app",
 					RunE: ExecHandler(ctx, func(help func()) (Handler, error) {
 						return HandlerFunc(func(ctx context.Context, _, _ []string) error {
 							log := LoggerFromContext(ctx)
@@ -43,19 +50,22 @@ func Test_WithLogger(t *testing.T) {
 						}), nil
 					}),
 				}, ctx, nil
-			})).Exec(context.Background(), []string{"-f", "json"})
+			}, LoggerWithCreateFunc(func(cfg logger.Config) (logger.Logger, error) {
+				return logruslogger.New(logruslogger.WithConfig(cfg))
+			}))).Exec(context.Background(), []string{"-f", "json"})
 			assert.NoError(t, err)
 		})
 		require.NoError(t, err)
 		var output map[string]interface{} // only one json log is supposed to be wrote
 		require.NoError(t, json.Unmarshal([]byte(outputRaw), &output), outputRaw)
 		assert.Empty(t, cmp.Diff(map[string]interface{}{ // make sure we get the right log
-			"level": logrus.InfoLevel.String(),
-			"hello": "world",
-			"msg":   "displayed",
+			"level":   logrus.InfoLevel.String(),
+			"hello":   "world",
+			"msg":     "displayed",
+			"command": "cli-app",
 		}, output,
 			cmpopts.IgnoreMapEntries(func(key string, _ interface{}) bool {
-				return key == "time"
+				return key == "time" || key == "invocation_id"
 			}),
 		))
 	})
@@ -73,6 +83,52 @@ func Test_WithLogger(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("standard log outputs are the command streams", func(t *testing.T) {
+		for output, expectedOnOut := range map[string]bool{LogOutputStderr: false, LogOutputStdout: true} {
+			var stdout, stderr bytes.Buffer
+			err := Command(WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+				return &cobra.Command{Use: "cli-app", Run: func(*cobra.Command, []string) {}}, ctx, nil
+			}, LoggerWithCreateWriterFunc(func(_ logger.Config, w io.Writer) (logger.Logger, error) {
+				_, err := w.Write([]byte("log"))
+				return &logger.Noop{}, err
+			}))).Exec(context.Background(), []string{"--log-output", output}, ExecWithIO(IO{Out: &stdout, Err: &stderr}))
+			require.NoError(t, err)
+
+			if expectedOnOut {
+				assert.Equal(t, "log", stdout.String(), output)
+				assert.Empty(t, stderr.String(), output)
+			} else {
+				assert.Empty(t, stdout.String(), output)
+				assert.Equal(t, "log", stderr.String(), output)
+			}
+		}
+	})
+
+	t.Run("backends opening the output get it unopened", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "logs")
+		newCLI := func(opts ...LoggerCommandOption) *CLI {
+			return Command(WithLogger(testCommand("cli-app", noopHandler), append([]LoggerCommandOption{LoggerWithCreateFunc(func(cfg logger.Config) (logger.Logger, error) {
+				assert.Equal(t, output, cfg.Output)
+				_, err := os.Stat(output)
+				assert.True(t, os.IsNotExist(err), "output should not be opened twice")
+				return &logger.Noop{}, nil
+			})}, opts...)...))
+		}
+
+		require.NoError(t, newCLI().Exec(context.Background(), []string{"--log-output", output}))
+
+		err := newCLI(LoggerWithSlog()).Exec(context.Background(), []string{"--log-output", output},
+			ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}))
+		assert.Error(t, err)
+	})
+
+	t.Run("default should require a logger backend", func(t *testing.T) {
+		err := Command(WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{Use: "cli-app", Run: func(*cobra.Command, []string) {}}, ctx, nil
+		})).Exec(context.Background(), []string{})
+		require.True(t, errors.Is(err, ErrLoggerBackendMissing))
+	})
+
 	t.Run("provided command failed to be built", func(t *testing.T) {
 		err := Command(WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
 			return nil, nil, errors.New("boum")
@@ -88,9 +144,12 @@ func Test_loggerPreRunInit(t *testing.T) {
 
 		cmd := cobra.Command{
 			PersistentPreRunE: loggerPreRunInit(
-				defaultLoggerCommandOptions().createLoggerFunc,
+				context.Background(),
+				noopLoggerCommandOptions(),
 				&cfg,
+				new(map[string]string),
 				new(logger.Logger),
+				nil,
 			),
 			SilenceErrors: true,
 			SilenceUsage:  true,
@@ -101,9 +160,9 @@ func Test_loggerPreRunInit(t *testing.T) {
 
 	t.Run("logger configuration is invalid", func(t *testing.T) {
 		cmd := cobra.Command{
-			PersistentPreRunE: loggerPreRunInit(defaultLoggerCommandOptions().createLoggerFunc, &logger.Config{
+			PersistentPreRunE: loggerPreRunInit(context.Background(), noopLoggerCommandOptions(), &logger.Config{
 				Formatter: "boum",
-			}, new(logger.Logger)),
+			}, new(map[string]string), new(logger.Logger), nil),
 			SilenceErrors: true,
 			SilenceUsage:  true,
 			Run:           func(*cobra.Command, []string) {},
@@ -111,18 +170,147 @@ func Test_loggerPreRunInit(t *testing.T) {
 		assert.Error(t, cmd.Execute())
 	})
 
+	t.Run("logger output is invalid", func(t *testing.T) {
+		var cfg logger.Config
+		cfg.SetDefault()
+		cfg.Output = filepath.Join(os.TempDir(), "404", "logs")
+
+		cmd := cobra.Command{
+			PersistentPreRunE: loggerPreRunInit(context.Background(), noopLoggerCommandOptions(), &cfg, new(map[string]string), new(logger.Logger), nil),
+			SilenceErrors:     true,
+			SilenceUsage:      true,
+			Run:               func(*cobra.Command, []string) {},
+		}
+		assert.Error(t, cmd.Execute())
+	})
+
 	t.Run("logger initialization failed", func(t *testing.T) {
 		var cfg logger.Config
 		cfg.SetDefault()
 
+		o := defaultLoggerCommandOptions()
+		LoggerWithCreateFunc(func(logger.Config) (logger.Logger, error) {
+			return nil, errors.New("boum")
+		})(o)
+
 		cmd := cobra.Command{
-			PersistentPreRunE: loggerPreRunInit(func(logger.Config) (logger.Logger, error) {
-				return nil, errors.New("boum")
-			}, &cfg, new(logger.Logger)),
-			SilenceErrors: true,
-			SilenceUsage:  true,
-			Run:           func(*cobra.Command, []string) {},
+			PersistentPreRunE: loggerPreRunInit(context.Background(), o, &cfg, new(map[string]string), new(logger.Logger), nil),
+			SilenceErrors:     true,
+			SilenceUsage:      true,
+			Run:               func(*cobra.Command, []string) {},
 		}
 		assert.Error(t, cmd.Execute())
 	})
 }
+
+func Test_WithLogger_enrichment(t *testing.T) {
+	newCLI := func(log *logger.InMemory, handle HandlerFunc) *CLI {
+		return Command(WithLogger(testCommand("app", noopHandler), LoggerWithVersion("1.2.3"), LoggerWithCreateFunc(func(cfg logger.Config) (logger.Logger, error) {
+			lvl, err := logger.ParseLevel(cfg.Verbosity)
+			if err != nil {
+				return nil, err
+			}
+			return log, log.SetLevel(lvl)
+		}))).SubCommand(Command(testCommand("db", noopHandler)).SubCommand(testCommand("migrate", handle)).Build())
+	}
+
+	t.Run("logger is enriched with command details", func(t *testing.T) {
+		log := logger.NewInMemory(logger.LevelInfo)
+		require.NoError(t, newCLI(log, func(ctx context.Context, _, _ []string) error {
+			LoggerFromContext(ctx).Info("hello")
+			return nil
+		}).Exec(context.Background(), []string{"db", "migrate"}))
+
+		require.Len(t, log.Entries, 1)
+		fields := log.Entries[0].Fields
+		assert.Equal(t, "db.migrate", fields["command"])
+		assert.Equal(t, "1.2.3", fields["version"])
+		assert.Len(t, fields["invocation_id"], 16)
+	})
+
+	t.Run("level is overridden for the most specific subcommand", func(t *testing.T) {
+		log := logger.NewInMemory(logger.LevelInfo)
+		require.NoError(t, newCLI(log, func(ctx context.Context, _, _ []string) error {
+			LoggerFromContext(ctx).Debug("hello")
+			return nil
+		}).Exec(context.Background(), []string{
+			"db", "migrate", "-v", "error", "--log-level-for", "db=info,db.migrate=debug",
+		}))
+		assert.Len(t, log.Entries, 1)
+	})
+
+	t.Run("overridden level is invalid", func(t *testing.T) {
+		log := logger.NewInMemory(logger.LevelInfo)
+		cli := newCLI(log, noopHandler)
+		err := cli.Exec(context.Background(), []string{"db", "migrate", "--log-level-for", "db=boum"},
+			ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}))
+		assert.Error(t, err)
+	})
+}
+
+func Test_WithLogger_app(t *testing.T) {
+	log := logger.NewInMemory(logger.LevelInfo)
+	var cmd *cobra.Command
+
+	cli := Command(WithApp(WithLogger(testCommand("app", func(ctx context.Context, _, _ []string) error {
+		LoggerFromContext(ctx).Info("hello")
+		return nil
+	}, func(c *cobra.Command) error {
+		c.Version, cmd = "1.0.0", c
+		return nil
+	}), LoggerWithVersion("1.2.3"), inMemoryLoggerCreateFunc(log)), AppWithVersion("2.0.0")))
+	require.NoError(t, cli.Exec(context.Background(), []string{}))
+
+	require.Len(t, log.Entries, 1)
+	assert.Equal(t, "2.0.0", log.Entries[0].Fields["version"])
+	assert.Equal(t, "app", cmd.Use)
+	assert.Equal(t, "2.0.0", cmd.Version)
+}
+
+func Test_WithLogger_deprecatedAppOptions(t *testing.T) {
+	cmd, _, err := WithLogger(testCommand("app", nil), LoggerWithAppName("go-app"), LoggerWithVersion("1.2.3"), noopLoggerCreateFunc())(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "go-app", cmd.Use)
+	assert.Equal(t, "1.2.3", cmd.Version)
+}
+
+func Test_loggerCommandKey(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	sub := &cobra.Command{Use: "sub"}
+	subsub := &cobra.Command{Use: "subsub"}
+	root.AddCommand(sub)
+	sub.AddCommand(subsub)
+
+	assert.Equal(t, "app", loggerCommandKey(root))
+	assert.Equal(t, "sub", loggerCommandKey(sub))
+	assert.Equal(t, "sub.subsub", loggerCommandKey(subsub))
+}
+
+func Test_levelOverrideFor(t *testing.T) {
+	overrides := map[string]string{"db": "info", "db.migrate": "debug", "d": "error"}
+
+	verbosity, found := levelOverrideFor("db.migrate.up", overrides)
+	assert.True(t, found)
+	assert.Equal(t, "debug", verbosity)
+
+	verbosity, found = levelOverrideFor("db.seed", overrides)
+	assert.True(t, found)
+	assert.Equal(t, "info", verbosity)
+
+	_, found = levelOverrideFor("dbx", overrides)
+	assert.False(t, found)
+}
+
+func noopLoggerCreateFunc() LoggerCommandOption {
+	return LoggerWithCreateWriterFunc(func(logger.Config, io.Writer) (logger.Logger, error) { return &logger.Noop{}, nil })
+}
+
+func inMemoryLoggerCreateFunc(log *logger.InMemory) LoggerCommandOption {
+	return LoggerWithCreateFunc(func(logger.Config) (logger.Logger, error) { return log, nil })
+}
+
+func noopLoggerCommandOptions() *loggerCommandOptions {
+	o := defaultLoggerCommandOptions()
+	noopLoggerCreateFunc()(o)
+	return o
+}
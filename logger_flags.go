@@ -0,0 +1,141 @@
+package clix
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/pflag"
+)
+
+// LoggerCountedVerbosityFlags is an alternative flag set for WithLogger, to use with
+// LoggerWithPersistentFlagsFunc, where verbosity is stepped by counted flags:
+// warnings and errors are displayed by default, -v displays informative logs,
+// -vv and above displays debug logs, and -q silences all logs.
+// The verbosity can still be explicitly set with --log-verbosity,
+// which can't be combined with -v or -q.
+func LoggerCountedVerbosityFlags(flags *pflag.FlagSet, cfg *logger.Config) {
+	cfg.Verbosity = logger.LevelWarn.String()
+	verbosity := &countedVerbosity{cfg: cfg, base: logger.LevelWarn}
+
+	flags.VarPF((*verbosityCountValue)(verbosity),
+		"verbose", "v",
+		"increase the verbosity of logs, can be repeated like -vv",
+	).NoOptDefVal = "+1"
+	flags.VarPF((*verbosityQuietValue)(verbosity),
+		"quiet", "q",
+		"silence all logs",
+	).NoOptDefVal = "true"
+	flags.Var((*verbosityExplicitValue)(verbosity),
+		"log-verbosity",
+		"verbosity of logs printed to the log output",
+	)
+	flags.StringVarP(&cfg.Formatter,
+		"log-format", "f", cfg.Formatter,
+		"format to print logs to the log output with",
+	)
+	flags.StringVar(&cfg.Output,
+		"log-output", cfg.Output,
+		"where to print logs to, one of stderr|stdout|<file path>",
+	)
+}
+
+var (
+	errVerboseWithQuiet    = errors.New("verbose and quiet flags can't be used together")
+	errVerbosityWithCounts = errors.New("log verbosity can't be used with verbose or quiet flags")
+)
+
+type countedVerbosity struct {
+	cfg      *logger.Config
+	base     logger.Level
+	count    int
+	quiet    bool
+	explicit bool
+}
+
+func (v *countedVerbosity) apply() {
+	level := v.base - logger.Level(v.count)
+	if level < logger.LevelDebug {
+		level = logger.LevelDebug
+	}
+	v.cfg.Verbosity = level.String()
+}
+
+// verbosityCountValue implements pflag.Value for the counted verbose flag.
+type verbosityCountValue countedVerbosity
+
+func (v *verbosityCountValue) String() string { return strconv.Itoa(v.count) }
+
+func (v *verbosityCountValue) Set(raw string) error {
+	switch {
+	case v.quiet:
+		return errVerboseWithQuiet
+	case v.explicit:
+		return errVerbosityWithCounts
+	}
+
+	if raw == "+1" {
+		v.count++
+	} else {
+		count, err := strconv.Atoi(raw)
+		if err != nil || count < 0 {
+			return errors.New("verbose flag expects a positive number")
+		}
+		v.count = count
+	}
+
+	(*countedVerbosity)(v).apply()
+	return nil
+}
+
+func (v *verbosityCountValue) Type() string { return "count" }
+
+// verbosityQuietValue implements pflag.Value for the quiet flag.
+type verbosityQuietValue countedVerbosity
+
+func (v *verbosityQuietValue) String() string { return strconv.FormatBool(v.quiet) }
+
+func (v *verbosityQuietValue) Set(raw string) error {
+	quiet, err := strconv.ParseBool(raw)
+	if err != nil {
+		return errors.New("quiet flag expects a boolean")
+	}
+
+	switch {
+	case quiet && v.count > 0:
+		return errVerboseWithQuiet
+	case v.explicit:
+		return errVerbosityWithCounts
+	}
+
+	v.quiet = quiet
+	if quiet {
+		v.cfg.Verbosity = logger.LevelQuiet.String()
+	} else {
+		(*countedVerbosity)(v).apply()
+	}
+	return nil
+}
+
+func (v *verbosityQuietValue) Type() string { return "bool" }
+
+// verbosityExplicitValue implements pflag.Value for the explicit verbosity flag.
+type verbosityExplicitValue countedVerbosity
+
+func (v *verbosityExplicitValue) String() string {
+	if v.cfg == nil {
+		return ""
+	}
+	return v.cfg.Verbosity
+}
+
+func (v *verbosityExplicitValue) Set(raw string) error {
+	if v.count > 0 || v.quiet {
+		return errVerbosityWithCounts
+	}
+	v.explicit = true
+	v.cfg.Verbosity = raw
+	return nil
+}
+
+func (v *verbosityExplicitValue) Type() string { return "string" }
@@ -0,0 +1,77 @@
+package clix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoggerCountedVerbosityFlags(t *testing.T) {
+	for name, test := range map[string]struct {
+		args              []string
+		expectedVerbosity string
+		expectedErr       bool
+	}{
+		"default":                      {expectedVerbosity: "warn"},
+		"one verbose":                  {args: []string{"-v"}, expectedVerbosity: "info"},
+		"two verbose":                  {args: []string{"-vv"}, expectedVerbosity: "debug"},
+		"more verbose than possible":   {args: []string{"-vvvv"}, expectedVerbosity: "debug"},
+		"repeated verbose":             {args: []string{"-v", "--verbose"}, expectedVerbosity: "debug"},
+		"explicit count":               {args: []string{"--verbose=1"}, expectedVerbosity: "info"},
+		"invalid count":                {args: []string{"--verbose=a"}, expectedErr: true},
+		"quiet":                        {args: []string{"-q"}, expectedVerbosity: "quiet"},
+		"not quiet":                    {args: []string{"--quiet=false"}, expectedVerbosity: "warn"},
+		"invalid quiet":                {args: []string{"--quiet=a"}, expectedErr: true},
+		"explicit verbosity":           {args: []string{"--log-verbosity", "error"}, expectedVerbosity: "error"},
+		"verbose with quiet":           {args: []string{"-v", "-q"}, expectedErr: true},
+		"quiet with verbose":           {args: []string{"-q", "-v"}, expectedErr: true},
+		"verbose with explicit":        {args: []string{"-v", "--log-verbosity", "info"}, expectedErr: true},
+		"explicit with verbose":        {args: []string{"--log-verbosity", "info", "-v"}, expectedErr: true},
+		"explicit with quiet":          {args: []string{"--log-verbosity", "info", "-q"}, expectedErr: true},
+		"format and output still work": {args: []string{"-f", "json", "--log-output", "stdout"}, expectedVerbosity: "warn"},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var cfg logger.Config
+			cfg.SetDefault()
+
+			flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+			flags.SetOutput(new(nopWriter))
+			LoggerCountedVerbosityFlags(flags, &cfg)
+
+			err := flags.Parse(test.args)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedVerbosity, cfg.Verbosity)
+		})
+	}
+}
+
+func Test_WithLogger_quiet(t *testing.T) {
+	err := Command(WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{
+			RunE: ExecHandler(ctx, func(func()) (Handler, error) {
+				return HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					assert.IsType(t, logger.Noop{}, LoggerFromContext(ctx))
+					return nil
+				}), nil
+			}),
+		}, ctx, nil
+	}, LoggerWithPersistentFlagsFunc(LoggerCountedVerbosityFlags), LoggerWithCreateFunc(func(logger.Config) (logger.Logger, error) {
+		t.Fatal("backend should not be created")
+		return nil, nil
+	}))).Exec(context.Background(), []string{"-q"})
+	require.NoError(t, err)
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,190 @@
+package clix
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log outputs that are not file paths.
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
+)
+
+// rotatedFileLayout is the layout of the rotation date suffixing rotated files.
+const rotatedFileLayout = "20060102T150405.000000000"
+
+// LogOutputRotation defines when a file log output is rotated.
+// Zero values disable the associated rotation trigger.
+type LogOutputRotation struct {
+	// MaxSize is the size in bytes the file can grow up to before being rotated.
+	MaxSize int64
+	// MaxAge is the duration after which the file is rotated.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep.
+	MaxBackups int
+}
+
+func (r LogOutputRotation) enabled() bool { return r.MaxSize > 0 || r.MaxAge > 0 }
+
+func isLogOutputFile(output string) bool {
+	return output != LogOutputStdout && output != LogOutputStderr && output != ""
+}
+
+// openLogOutput opens the log output, standard outputs being the provided streams.
+// File outputs are rotating files, to close once logs are no longer written.
+func openLogOutput(output string, rotation LogOutputRotation, streams IO) (io.Writer, error) {
+	switch output {
+	case LogOutputStdout:
+		return streams.Out, nil
+	case LogOutputStderr, "":
+		return streams.Err, nil
+	}
+
+	if info, err := os.Stat(filepath.Dir(output)); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("log output directory of %q does not exist", output)
+	}
+
+	w := &rotatingFile{path: output, rotation: rotation, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// rotatingFile is a file writer that rotates the file
+// following the provided rotation policy.
+type rotatingFile struct {
+	path     string
+	rotation LogOutputRotation
+	now      func() time.Time
+
+	m        sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open/create file %q: %w", f.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close() // nolint: errcheck, gosec
+		return fmt.Errorf("unable to stat file %q: %w", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// Write implements io.Writer.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close implements io.Closer.
+func (f *rotatingFile) Close() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("unable to close file %q: %w", f.path, err)
+	}
+	return nil
+}
+
+func (f *rotatingFile) shouldRotate(incoming int) bool {
+	if !f.rotation.enabled() || f.size == 0 {
+		return false
+	}
+	if f.rotation.MaxSize > 0 && f.size+int64(incoming) > f.rotation.MaxSize {
+		return true
+	}
+	return f.rotation.MaxAge > 0 && f.now().Sub(f.openedAt) > f.rotation.MaxAge
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("unable to close file %q: %w", f.path, err)
+	}
+
+	backup := f.path + "." + f.now().UTC().Format(rotatedFileLayout)
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("unable to rotate file %q: %w", f.path, err)
+	}
+
+	if err := f.removeOldBackups(); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) removeOldBackups() error {
+	if f.rotation.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := f.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= f.rotation.MaxBackups {
+		return nil
+	}
+
+	for _, backup := range backups[:len(backups)-f.rotation.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("unable to remove rotated file %q: %w", backup, err)
+		}
+	}
+	return nil
+}
+
+// rotatedFiles returns the files rotation created, sorted by rotation date.
+// Only files suffixed by a rotation date are considered, to never remove other files.
+func (f *rotatingFile) rotatedFiles() ([]string, error) {
+	entries, err := ioutil.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list rotated files: %w", err)
+	}
+
+	prefix := filepath.Base(f.path) + "."
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, err := time.Parse(rotatedFileLayout, strings.TrimPrefix(name, prefix)); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(f.path), name))
+	}
+
+	sort.Strings(backups) // backups are suffixed by their rotation date
+	return backups, nil
+}
@@ -0,0 +1,160 @@
+package clix
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_openLogOutput(t *testing.T) {
+	t.Run("standard outputs", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		streams := IO{Out: &stdout, Err: &stderr}
+
+		w, err := openLogOutput(LogOutputStdout, LogOutputRotation{}, streams)
+		require.NoError(t, err)
+		assert.Equal(t, &stdout, w)
+
+		w, err = openLogOutput(LogOutputStderr, LogOutputRotation{}, streams)
+		require.NoError(t, err)
+		assert.Equal(t, &stderr, w)
+
+		w, err = openLogOutput("", LogOutputRotation{}, streams)
+		require.NoError(t, err)
+		assert.Equal(t, &stderr, w)
+	})
+
+	t.Run("file output", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "clix-log-output")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir) // nolint: errcheck, gosec
+		path := filepath.Join(dir, "logs")
+
+		w, err := openLogOutput(path, LogOutputRotation{}, IO{})
+		require.NoError(t, err)
+		_, err = w.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, w.(io.Closer).Close())
+
+		raw, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(raw))
+
+		_, err = openLogOutput(filepath.Join(dir, "404", "logs"), LogOutputRotation{}, IO{})
+		assert.Error(t, err)
+		_, err = openLogOutput(dir, LogOutputRotation{}, IO{})
+		assert.Error(t, err)
+	})
+}
+
+func Test_rotatingFile(t *testing.T) {
+	newRotatingFile := func(t *testing.T, rotation LogOutputRotation) (*rotatingFile, *time.Time, func()) {
+		dir, err := ioutil.TempDir("", "clix-log-rotation")
+		require.NoError(t, err)
+
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		f := &rotatingFile{
+			path:     filepath.Join(dir, "logs"),
+			rotation: rotation,
+			now:      func() time.Time { return now },
+		}
+		require.NoError(t, f.open())
+
+		return f, &now, func() {
+			f.file.Close()    // nolint: errcheck, gosec
+			os.RemoveAll(dir) // nolint: errcheck, gosec
+		}
+	}
+
+	files := func(t *testing.T, f *rotatingFile) []string {
+		matches, err := filepath.Glob(f.path + "*")
+		require.NoError(t, err)
+		sort.Strings(matches)
+
+		var contents []string
+		for _, match := range matches {
+			raw, err := ioutil.ReadFile(match)
+			require.NoError(t, err)
+			contents = append(contents, string(raw))
+		}
+		return contents
+	}
+
+	t.Run("rotates on size", func(t *testing.T) {
+		f, now, clean := newRotatingFile(t, LogOutputRotation{MaxSize: 4})
+		defer clean()
+
+		for _, line := range []string{"aa", "bb", "cc"} {
+			_, err := f.Write([]byte(line))
+			require.NoError(t, err)
+			*now = now.Add(time.Second)
+		}
+		assert.Equal(t, []string{"cc", "aabb"}, files(t, f))
+	})
+
+	t.Run("rotates on age", func(t *testing.T) {
+		f, now, clean := newRotatingFile(t, LogOutputRotation{MaxAge: time.Minute})
+		defer clean()
+
+		for _, line := range []string{"aa", "bb", "cc"} {
+			_, err := f.Write([]byte(line))
+			require.NoError(t, err)
+			*now = now.Add(40 * time.Second)
+		}
+		assert.Equal(t, []string{"cc", "aabb"}, files(t, f))
+	})
+
+	t.Run("keeps a limited number of backups", func(t *testing.T) {
+		f, now, clean := newRotatingFile(t, LogOutputRotation{MaxSize: 1, MaxBackups: 2})
+		defer clean()
+
+		for _, line := range []string{"a", "b", "c", "d"} {
+			_, err := f.Write([]byte(line))
+			require.NoError(t, err)
+			*now = now.Add(time.Second)
+		}
+		assert.Equal(t, []string{"d", "b", "c"}, files(t, f))
+	})
+
+	t.Run("only removes rotated files", func(t *testing.T) {
+		f, now, clean := newRotatingFile(t, LogOutputRotation{MaxSize: 1, MaxBackups: 1})
+		defer clean()
+
+		unrelated := []string{f.path + ".gz", f.path + ".lock", f.path + ".20200101"}
+		for _, path := range unrelated {
+			require.NoError(t, ioutil.WriteFile(path, []byte("x"), 0o600))
+		}
+
+		for _, line := range []string{"a", "b", "c"} {
+			_, err := f.Write([]byte(line))
+			require.NoError(t, err)
+			*now = now.Add(time.Second)
+		}
+
+		backups, err := f.rotatedFiles()
+		require.NoError(t, err)
+		assert.Equal(t, []string{f.path + ".20200101T000002.000000000"}, backups)
+		for _, path := range unrelated {
+			assert.FileExists(t, path)
+		}
+	})
+
+	t.Run("does not rotate without policy", func(t *testing.T) {
+		f, _, clean := newRotatingFile(t, LogOutputRotation{})
+		defer clean()
+
+		for _, line := range []string{"a", "b"} {
+			_, err := f.Write([]byte(line))
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"ab"}, files(t, f))
+	})
+}
@@ -0,0 +1,96 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/krostar/logger"
+)
+
+const ctxKeySlog ctxKey = "slog"
+
+// SlogFromContext returns the standard library structured logger from the context,
+// if present. It is only created by WithLogger when used with LoggerWithSlog.
+func SlogFromContext(ctx context.Context) *slog.Logger {
+	if logPtr, hasLogger := ctx.Value(ctxKeySlog).(*slog.Logger); hasLogger && logPtr != nil && logPtr.Handler() != nil {
+		return logPtr
+	}
+	return nil
+}
+
+type slogOptions struct {
+	addSource bool
+	level     slog.Leveler
+}
+
+// SlogOption defines the signature of a slog option applier.
+type SlogOption func(o *slogOptions)
+
+// SlogWithSource adds the source code location of the log statement to every logs.
+func SlogWithSource() SlogOption {
+	return func(o *slogOptions) { o.addSource = true }
+}
+
+// SlogWithLevel overrides the level defined by the logger configuration.
+// Levels set for the command with --log-level-for still take precedence.
+func SlogWithLevel(level slog.Leveler) SlogOption {
+	return func(o *slogOptions) { o.level = level }
+}
+
+// slogLevelQuiet is above every level slog logs at.
+const slogLevelQuiet = slog.LevelError + 4
+
+// SlogLevel returns the slog level logging at the same verbosity as the provided level.
+func SlogLevel(level logger.Level) (slog.Level, error) {
+	switch level {
+	case logger.LevelDebug:
+		return slog.LevelDebug, nil
+	case logger.LevelInfo:
+		return slog.LevelInfo, nil
+	case logger.LevelWarn:
+		return slog.LevelWarn, nil
+	case logger.LevelError:
+		return slog.LevelError, nil
+	case logger.LevelQuiet:
+		return slogLevelQuiet, nil
+	default:
+		return 0, errors.New("level conversion to slog level impossible")
+	}
+}
+
+// NewSlogHandler creates a slog handler writing logs to the provided writer,
+// with the format defined by the logger configuration.
+func NewSlogHandler(cfg logger.Config, w io.Writer, opts *slog.HandlerOptions) (slog.Handler, error) {
+	switch cfg.Formatter {
+	case "json":
+		return slog.NewJSONHandler(w, opts), nil
+	case "console":
+		return slog.NewTextHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown formatter %q", cfg.Formatter)
+	}
+}
+
+// newSlog creates the slog logger of WithLogger. The level is the one of the configuration
+// when overridden for the command with --log-level-for, or when none is set by SlogWithLevel.
+func newSlog(cfg logger.Config, w io.Writer, o *slogOptions, overridden bool) (*slog.Logger, error) {
+	level := o.level
+	if level == nil || overridden {
+		lvl, err := logger.ParseLevel(cfg.Verbosity)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse level %q: %w", cfg.Verbosity, err)
+		}
+		if level, err = SlogLevel(lvl); err != nil {
+			return nil, err
+		}
+	}
+
+	handler, err := NewSlogHandler(cfg, w, &slog.HandlerOptions{AddSource: o.addSource, Level: level})
+	if err != nil {
+		return nil, err
+	}
+	return slog.New(handler), nil
+}
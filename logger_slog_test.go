@@ -0,0 +1,124 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SlogFromContext(t *testing.T) {
+	t.Run("with slog in context", func(t *testing.T) {
+		log := slog.New(slog.NewTextHandler(ioutil.Discard, nil))
+		ctx := context.WithValue(context.Background(), ctxKeySlog, log)
+		assert.Equal(t, log, SlogFromContext(ctx))
+	})
+	t.Run("with uninitialized slog in context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKeySlog, new(slog.Logger))
+		assert.Nil(t, SlogFromContext(ctx))
+	})
+	t.Run("without slog in context", func(t *testing.T) {
+		assert.Nil(t, SlogFromContext(context.Background()))
+	})
+}
+
+func Test_WithLogger_slog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clix-slog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck, gosec
+	path := filepath.Join(dir, "logs.json")
+
+	err = Command(WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{
+			RunE: ExecHandler(ctx, func(func()) (Handler, error) {
+				return HandlerFunc(func(ctx context.Context, _, _ []string) error {
+					log := SlogFromContext(ctx)
+					require.NotNil(t, log)
+					log.Info("hidden")
+					log.Warn("displayed", "hello", "world")
+					return nil
+				}), nil
+			}),
+		}, ctx, nil
+	}, LoggerWithCreateWriterFunc(func(logger.Config, io.Writer) (logger.Logger, error) {
+		return &logger.Noop{}, nil
+	}), LoggerWithSlog(SlogWithSource()), LoggerWithPersistentFlagsFunc(func(_ *pflag.FlagSet, cfg *logger.Config) {
+		cfg.Verbosity = "warn"
+		cfg.Formatter = "json"
+		cfg.Output = path
+	}))).Exec(context.Background(), []string{})
+	require.NoError(t, err)
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &entry))
+	assert.Equal(t, "displayed", entry["msg"])
+	assert.Equal(t, "world", entry["hello"])
+	assert.Contains(t, entry, slog.SourceKey)
+}
+
+func Test_newSlog(t *testing.T) {
+	t.Run("level option", func(t *testing.T) {
+		log, err := newSlog(logger.Config{Verbosity: "error", Formatter: "console"}, ioutil.Discard, &slogOptions{level: slog.LevelDebug}, false)
+		require.NoError(t, err)
+		assert.True(t, log.Enabled(context.Background(), slog.LevelDebug))
+	})
+
+	t.Run("command level override takes precedence over the level option", func(t *testing.T) {
+		log, err := newSlog(logger.Config{Verbosity: "error", Formatter: "console"}, ioutil.Discard, &slogOptions{level: slog.LevelDebug}, true)
+		require.NoError(t, err)
+		assert.False(t, log.Enabled(context.Background(), slog.LevelWarn))
+		assert.True(t, log.Enabled(context.Background(), slog.LevelError))
+	})
+
+	t.Run("invalid configurations", func(t *testing.T) {
+		_, err := newSlog(logger.Config{Verbosity: "boum", Formatter: "json"}, ioutil.Discard, new(slogOptions), false)
+		assert.Error(t, err)
+		_, err = newSlog(logger.Config{Formatter: "boum"}, ioutil.Discard, new(slogOptions), false)
+		assert.Error(t, err)
+	})
+}
+
+func Test_NewSlogHandler(t *testing.T) {
+	handler, err := NewSlogHandler(logger.Config{Formatter: "json"}, ioutil.Discard, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &slog.JSONHandler{}, handler)
+
+	handler, err = NewSlogHandler(logger.Config{Formatter: "console"}, ioutil.Discard, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &slog.TextHandler{}, handler)
+
+	_, err = NewSlogHandler(logger.Config{Formatter: "boum"}, ioutil.Discard, nil)
+	assert.Error(t, err)
+}
+
+func Test_SlogLevel(t *testing.T) {
+	for level, expected := range map[logger.Level]slog.Level{
+		logger.LevelDebug: slog.LevelDebug,
+		logger.LevelInfo:  slog.LevelInfo,
+		logger.LevelWarn:  slog.LevelWarn,
+		logger.LevelError: slog.LevelError,
+	} {
+		converted, err := SlogLevel(level)
+		require.NoError(t, err)
+		assert.Equal(t, expected, converted)
+	}
+
+	quiet, err := SlogLevel(logger.LevelQuiet)
+	require.NoError(t, err)
+	assert.True(t, quiet > slog.LevelError)
+
+	_, err = SlogLevel(logger.Level(42))
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package clix
+
+import (
+	"context"
+)
+
+const ctxKeyMiddlewares ctxKey = "middlewares"
+
+// Middleware wraps a handler to add a behavior around it, like timing.
+type Middleware func(Handler) Handler
+
+// Use attaches middlewares to the handlers of the command and all its subcommands.
+// Middlewares are applied in order, the first one being the outermost, and
+// middlewares attached to a command wrap the ones attached to its subcommands.
+func (cli *CLI) Use(middlewares ...Middleware) *CLI {
+	cli.middlewares = append(cli.middlewares, middlewares...)
+	return cli
+}
+
+func middlewaresFromContext(ctx context.Context) []Middleware {
+	middlewares, _ := ctx.Value(ctxKeyMiddlewares).([]Middleware)
+	return middlewares
+}
+
+func withMiddlewares(ctx context.Context, middlewares []Middleware) context.Context {
+	if len(middlewares) == 0 {
+		return ctx
+	}
+
+	inherited := middlewaresFromContext(ctx)
+	all := make([]Middleware, 0, len(inherited)+len(middlewares))
+	all = append(all, inherited...)
+	all = append(all, middlewares...)
+	return context.WithValue(ctx, ctxKeyMiddlewares, all)
+}
+
+// applyMiddlewares wraps the invocation handler with the middlewares from the context,
+// and the default recover middleware.
+// The invocation provided to the handler reflects the arguments the middlewares called it with.
+func applyMiddlewares(ctx context.Context, handler InvocationHandler) Handler {
+	var h Handler = HandlerFunc(func(ctx context.Context, args []string, dashedArgs []string) error {
+		inv, _ := InvocationFromContext(ctx)
+		inv.Args, inv.DashedArgs = args, dashedArgs
+		return handler.HandleInvocation(context.WithValue(ctx, ctxKeyInvocation, inv), inv)
+	})
+
+	middlewares := middlewaresFromContext(ctx)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return Recover()(h)
+}
@@ -0,0 +1,91 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CLI_Use(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, args, dashedArgs []string) error {
+				calls = append(calls, name+" in")
+				err := next.Handle(ctx, args, dashedArgs)
+				calls = append(calls, name+" out")
+				return err
+			})
+		}
+	}
+
+	newCLI := func(handle HandlerFunc) *CLI {
+		return Command(testCommand("app", nil)).Use(trace("a"), trace("b")).SubCommand(
+			Command(testCommand("sub", nil)).Use(trace("c")).SubCommand(testCommand("subsub", handle)).Build(),
+		)
+	}
+
+	t.Run("middlewares are applied in order and inherited", func(t *testing.T) {
+		calls = nil
+		require.NoError(t, newCLI(func(context.Context, []string, []string) error {
+			calls = append(calls, "handler")
+			return nil
+		}).Exec(context.Background(), []string{"sub", "subsub"}))
+		assert.Equal(t, []string{"a in", "b in", "c in", "handler", "c out", "b out", "a out"}, calls)
+	})
+
+	t.Run("handler errors are returned through middlewares", func(t *testing.T) {
+		calls = nil
+		err := newCLI(func(context.Context, []string, []string) error {
+			return errors.New("boum")
+		}).Exec(context.Background(), []string{"sub", "subsub"}, ExecWithIO(IO{Out: new(nopWriter), Err: new(nopWriter)}))
+		assert.EqualError(t, err, "boum")
+		assert.Len(t, calls, 6)
+	})
+}
+
+func Test_applyMiddlewares(t *testing.T) {
+	type ctxKeyTest struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKeyMiddlewares, []Middleware{
+		func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, args, dashedArgs []string) error {
+				return next.Handle(context.WithValue(ctx, ctxKeyTest{}, "value"), append(args, "added"), dashedArgs)
+			})
+		},
+	})
+	ctx = context.WithValue(ctx, ctxKeyInvocation, Invocation{CommandPath: "app", Args: []string{"arg"}})
+
+	var (
+		handledCtx context.Context
+		handledInv Invocation
+	)
+	err := applyMiddlewares(ctx, InvocationHandlerFunc(func(ctx context.Context, inv Invocation) error {
+		handledCtx, handledInv = ctx, inv
+		return nil
+	})).Handle(ctx, []string{"arg"}, []string{"dashed"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", handledCtx.Value(ctxKeyTest{}))
+	assert.Equal(t, Invocation{CommandPath: "app", Args: []string{"arg", "added"}, DashedArgs: []string{"dashed"}}, handledInv)
+	inv, _ := InvocationFromContext(handledCtx)
+	assert.Equal(t, handledInv, inv)
+}
+
+func Test_withMiddlewares(t *testing.T) {
+	noop := func(next Handler) Handler { return next }
+
+	ctx := withMiddlewares(context.Background(), nil)
+	assert.Nil(t, middlewaresFromContext(ctx))
+
+	ctx = withMiddlewares(ctx, []Middleware{noop})
+	parent := middlewaresFromContext(ctx)
+	assert.Len(t, parent, 1)
+
+	child := withMiddlewares(ctx, []Middleware{noop, noop})
+	assert.Len(t, middlewaresFromContext(child), 3)
+	assert.Len(t, middlewaresFromContext(ctx), 1, "parent middlewares are left untouched")
+}
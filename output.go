@@ -0,0 +1,203 @@
+package clix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats names understood by Render.
+const (
+	OutputFormatJSON     = "json"
+	OutputFormatYAML     = "yaml"
+	OutputFormatTable    = "table"
+	OutputFormatTemplate = "template"
+)
+
+// OutputFormat defines the way values are rendered.
+type OutputFormat struct {
+	Name     string
+	Template string
+}
+
+func (f OutputFormat) validate() error {
+	switch f.Name {
+	case OutputFormatJSON, OutputFormatYAML, OutputFormatTable:
+		if f.Template != "" {
+			return fmt.Errorf("output format %q takes no parameter", f.Name)
+		}
+	case OutputFormatTemplate:
+		if f.Template == "" {
+			return errors.New("template format requires a template")
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", f.Name)
+	}
+	return nil
+}
+
+// outputFormatValue implements pflag.Value for OutputFormat.
+type outputFormatValue OutputFormat
+
+func (v *outputFormatValue) String() string {
+	if v.Name == OutputFormatTemplate {
+		return v.Name + "=" + v.Template
+	}
+	return v.Name
+}
+
+func (v *outputFormatValue) Set(raw string) error {
+	name, tmpl := raw, ""
+	if i := strings.Index(raw, "="); i >= 0 {
+		name, tmpl = raw[:i], raw[i+1:]
+		if name != OutputFormatTemplate {
+			return fmt.Errorf("output format %q takes no parameter", name)
+		}
+	}
+
+	format := OutputFormat{Name: name, Template: tmpl}
+	if err := format.validate(); err != nil {
+		return err
+	}
+
+	*v = outputFormatValue(format)
+	return nil
+}
+
+func (v *outputFormatValue) Type() string { return "format" }
+
+// Output renders values to a writer using a format.
+type Output struct {
+	Format OutputFormat
+	Writer io.Writer
+}
+
+// Render writes the value to the writer using the output format.
+func (o *Output) Render(value interface{}) error {
+	var err error
+
+	switch o.Format.Name {
+	case OutputFormatJSON:
+		encoder := json.NewEncoder(o.Writer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(value)
+	case OutputFormatYAML:
+		var raw []byte
+		if raw, err = yaml.Marshal(value); err == nil {
+			_, err = o.Writer.Write(raw)
+		}
+	case OutputFormatTable:
+		err = renderTable(o.Writer, value)
+	case OutputFormatTemplate:
+		var tmpl *template.Template
+		if tmpl, err = template.New("output").Parse(o.Format.Template); err == nil {
+			err = tmpl.Execute(o.Writer, value)
+		}
+	default:
+		err = fmt.Errorf("unknown output format %q", o.Format.Name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to render %s output: %w", o.Format.Name, err)
+	}
+	return nil
+}
+
+// renderTable writes the value as an aligned table. Struct fields are used
+// as columns, the column name being the field's table tag if set,
+// the upper-cased field name otherwise; a "-" tag skips the field.
+func renderTable(w io.Writer, value interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	v := reflect.Indirect(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		var rows []reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			rows = append(rows, reflect.Indirect(v.Index(i)))
+		}
+		if elem := indirectType(v.Type().Elem()); elem.Kind() == reflect.Struct {
+			writeTableStructs(tw, elem, rows)
+		} else {
+			for _, row := range rows {
+				fmt.Fprintln(tw, formatTableCell(row))
+			}
+		}
+	case reflect.Struct:
+		writeTableStructs(tw, v.Type(), []reflect.Value{v})
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		fmt.Fprintln(tw, "KEY\tVALUE")
+		for _, key := range keys {
+			fmt.Fprintf(tw, "%v\t%s\n", key.Interface(), formatTableCell(v.MapIndex(key)))
+		}
+	case reflect.Invalid:
+	default:
+		fmt.Fprintln(tw, formatTableCell(v))
+	}
+
+	return tw.Flush()
+}
+
+func writeTableStructs(w io.Writer, typ reflect.Type, rows []reflect.Value) {
+	var (
+		headers []string
+		fields  []int
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		name := field.Tag.Get("table")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = strings.ToUpper(field.Name)
+		}
+		headers = append(headers, name)
+		fields = append(fields, i)
+	}
+
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(fields))
+		if row.IsValid() {
+			for i, field := range fields {
+				cells[i] = formatTableCell(row.Field(field))
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+}
+
+func formatTableCell(v reflect.Value) string {
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
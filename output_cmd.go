@@ -0,0 +1,64 @@
+package clix
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const ctxKeyOutput ctxKey = "output"
+
+// OutputFromContext returns the output from the context, if present.
+func OutputFromContext(ctx context.Context) *Output {
+	if output, hasOutput := ctx.Value(ctxKeyOutput).(*Output); hasOutput && output != nil {
+		return output
+	}
+	return nil
+}
+
+// WithOutput adds to an existing command the output flag, and
+// provides to subcommands an output writer to render values with.
+func WithOutput(cbf CommandBuilderFunc, opts ...OutputCommandOption) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		o := defaultOutputCommandOptions()
+		for _, opt := range opts {
+			opt(o)
+		}
+
+		output := &Output{Format: o.defaultFormat, Writer: os.Stdout}
+		ctx = context.WithValue(ctx, ctxKeyOutput, output)
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build root command: %w", err)
+		}
+
+		o.setPersistentFlags(cmd.PersistentFlags(), output)
+		appendPersistentPreRunE(cmd, outputPreRunInit(output))
+
+		return cmd, ctx, nil
+	}
+}
+
+func outputPreRunInit(output *Output) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := output.Format.validate(); err != nil {
+			return fmt.Errorf("output config is invalid: %w", err)
+		}
+		output.Writer = cmd.OutOrStdout()
+		return nil
+	}
+}
+
+// Render writes the provided value to the output found in the context,
+// using the configured format. Without output in the context, the value
+// is rendered as a table on the command output stream.
+func Render(ctx context.Context, value interface{}) error {
+	output := OutputFromContext(ctx)
+	if output == nil {
+		output = &Output{Format: OutputFormat{Name: OutputFormatTable}, Writer: IOFromContext(ctx).Out}
+	}
+	return output.Render(value)
+}
@@ -0,0 +1,35 @@
+package clix
+
+import (
+	"github.com/spf13/pflag"
+)
+
+type outputCommandOptions struct {
+	defaultFormat      OutputFormat
+	setPersistentFlags func(flags *pflag.FlagSet, output *Output)
+}
+
+func defaultOutputCommandOptions() *outputCommandOptions {
+	return &outputCommandOptions{
+		defaultFormat: OutputFormat{Name: OutputFormatTable},
+		setPersistentFlags: func(flags *pflag.FlagSet, output *Output) {
+			flags.VarP((*outputFormatValue)(&output.Format),
+				"output", "o",
+				"format to print results with, one of json|yaml|table|template=<go-template>",
+			)
+		},
+	}
+}
+
+// OutputCommandOption defines the signature of an option applier.
+type OutputCommandOption func(o *outputCommandOptions)
+
+// OutputWithDefaultFormat sets the format used when no output flag is provided.
+func OutputWithDefaultFormat(format OutputFormat) OutputCommandOption {
+	return func(o *outputCommandOptions) { o.defaultFormat = format }
+}
+
+// OutputWithPersistentFlagsFunc overrides the defaults persistent flag set.
+func OutputWithPersistentFlagsFunc(fct func(flags *pflag.FlagSet, output *Output)) OutputCommandOption {
+	return func(o *outputCommandOptions) { o.setPersistentFlags = fct }
+}
@@ -0,0 +1,42 @@
+package clix
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_defaultOutputCommandOptions(t *testing.T) {
+	o := defaultOutputCommandOptions()
+	assert.Equal(t, OutputFormat{Name: OutputFormatTable}, o.defaultFormat)
+
+	t.Run("long flag should set the output", func(t *testing.T) {
+		var output Output
+		flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+		o.setPersistentFlags(flags, &output)
+		require.NoError(t, flags.Parse([]string{"--output", "yaml"}))
+		assert.Equal(t, OutputFormat{Name: OutputFormatYAML}, output.Format)
+	})
+
+	t.Run("short flag should set the output", func(t *testing.T) {
+		var output Output
+		flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+		o.setPersistentFlags(flags, &output)
+		require.NoError(t, flags.Parse([]string{"-o", "template={{.}}"}))
+		assert.Equal(t, OutputFormat{Name: OutputFormatTemplate, Template: "{{.}}"}, output.Format)
+	})
+}
+
+func Test_OutputWithDefaultFormat(t *testing.T) {
+	var o outputCommandOptions
+	OutputWithDefaultFormat(OutputFormat{Name: OutputFormatJSON})(&o)
+	assert.Equal(t, OutputFormat{Name: OutputFormatJSON}, o.defaultFormat)
+}
+
+func Test_OutputWithPersistentFlagsFunc(t *testing.T) {
+	var o outputCommandOptions
+	OutputWithPersistentFlagsFunc(func(*pflag.FlagSet, *Output) {})(&o)
+	assert.NotNil(t, o.setPersistentFlags)
+}
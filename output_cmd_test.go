@@ -0,0 +1,94 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OutputFromContext(t *testing.T) {
+	t.Run("with output in context", func(t *testing.T) {
+		output := new(Output)
+		ctx := context.WithValue(context.Background(), ctxKeyOutput, output)
+		assert.Equal(t, output, OutputFromContext(ctx))
+	})
+	t.Run("without output in context", func(t *testing.T) {
+		assert.Nil(t, OutputFromContext(context.Background()))
+	})
+}
+
+func Test_WithOutput(t *testing.T) {
+	render := func(value interface{}) HandlerFunc {
+		return func(ctx context.Context, _, _ []string) error { return Render(ctx, value) }
+	}
+	newCLI := func(value interface{}) *CLI {
+		return Command(WithOutput(testCommand("app", noopHandler))).SubCommand(testCommand("sub", render(value), silenceErrors))
+	}
+
+	t.Run("default format is used without flag", func(t *testing.T) {
+		cmd, _, err := newCLI(outputTestItem{Name: "a", Count: 1}).Build()(context.Background())
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{"sub"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "NAME   TOTAL\na      1\n", buf.String())
+	})
+
+	t.Run("flag sets the format", func(t *testing.T) {
+		cmd, _, err := newCLI([]string{"a"}).Build()(context.Background())
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{"sub", "-o", "json"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "[\n  \"a\"\n]\n", buf.String())
+	})
+
+	t.Run("invalid flag value", func(t *testing.T) {
+		cmd, _, err := newCLI(nil).Build()(context.Background())
+		require.NoError(t, err)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"sub", "--output", "xml"})
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("options should be applied", func(t *testing.T) {
+		cmd, _, err := Command(WithOutput(testCommand("app", render([]int{1})), OutputWithDefaultFormat(OutputFormat{Name: OutputFormatYAML}))).Build()(context.Background())
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "- 1\n", buf.String())
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		err := Command(WithOutput(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		})).Exec(context.Background(), []string{})
+		require.Error(t, err)
+	})
+}
+
+func Test_outputPreRunInit(t *testing.T) {
+	t.Run("writer is set to the command output", func(t *testing.T) {
+		output := &Output{Format: OutputFormat{Name: OutputFormatJSON}}
+		var buf bytes.Buffer
+		cmd := cobra.Command{}
+		cmd.SetOut(&buf)
+		require.NoError(t, outputPreRunInit(output)(&cmd, nil))
+		assert.Equal(t, &buf, output.Writer)
+	})
+
+	t.Run("output configuration is invalid", func(t *testing.T) {
+		output := &Output{Format: OutputFormat{Name: "boum"}}
+		assert.Error(t, outputPreRunInit(output)(&cobra.Command{}, nil))
+	})
+}
@@ -0,0 +1,123 @@
+package clix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outputTestItem struct {
+	Name    string `json:"name" yaml:"name"`
+	Count   int    `json:"count" yaml:"count" table:"TOTAL"`
+	Ignored string `json:"-" yaml:"-" table:"-"`
+	hidden  string
+}
+
+func Test_outputFormatValue(t *testing.T) {
+	t.Run("simple format", func(t *testing.T) {
+		var v outputFormatValue
+		require.NoError(t, v.Set("json"))
+		assert.Equal(t, OutputFormat{Name: OutputFormatJSON}, OutputFormat(v))
+		assert.Equal(t, "json", v.String())
+	})
+
+	t.Run("template format", func(t *testing.T) {
+		var v outputFormatValue
+		require.NoError(t, v.Set("template={{.Name}}"))
+		assert.Equal(t, OutputFormat{Name: OutputFormatTemplate, Template: "{{.Name}}"}, OutputFormat(v))
+		assert.Equal(t, "template={{.Name}}", v.String())
+	})
+
+	t.Run("template format without template", func(t *testing.T) {
+		var v outputFormatValue
+		assert.Error(t, v.Set("template"))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		var v outputFormatValue
+		assert.Error(t, v.Set("xml"))
+	})
+
+	t.Run("parameter of a format without parameter", func(t *testing.T) {
+		for raw, name := range map[string]string{"json=foo": "json", "table=": "table", "yaml=x": "yaml"} {
+			var v outputFormatValue
+			assert.EqualError(t, v.Set(raw), `output format "`+name+`" takes no parameter`, raw)
+		}
+		assert.Error(t, OutputFormat{Name: OutputFormatJSON, Template: "{{.}}"}.validate())
+	})
+}
+
+func TestOutput_Render(t *testing.T) {
+	items := []outputTestItem{
+		{Name: "a", Count: 1, Ignored: "x", hidden: "y"},
+		{Name: "bbbb", Count: 22},
+	}
+
+	for name, test := range map[string]struct {
+		format   OutputFormat
+		value    interface{}
+		expected string
+	}{
+		"json": {
+			format:   OutputFormat{Name: OutputFormatJSON},
+			value:    items[0],
+			expected: "{\n  \"name\": \"a\",\n  \"count\": 1\n}\n",
+		},
+		"yaml": {
+			format:   OutputFormat{Name: OutputFormatYAML},
+			value:    items[0],
+			expected: "name: a\ncount: 1\n",
+		},
+		"template": {
+			format:   OutputFormat{Name: OutputFormatTemplate, Template: "{{range .}}{{.Name}};{{end}}"},
+			value:    items,
+			expected: "a;bbbb;",
+		},
+		"table of structs": {
+			format:   OutputFormat{Name: OutputFormatTable},
+			value:    items,
+			expected: "NAME   TOTAL\na      1\nbbbb   22\n",
+		},
+		"table of struct pointer": {
+			format:   OutputFormat{Name: OutputFormatTable},
+			value:    &items[1],
+			expected: "NAME   TOTAL\nbbbb   22\n",
+		},
+		"table of map": {
+			format:   OutputFormat{Name: OutputFormatTable},
+			value:    map[string]int{"b": 2, "a": 1},
+			expected: "KEY   VALUE\na     1\nb     2\n",
+		},
+		"table of scalars": {
+			format:   OutputFormat{Name: OutputFormatTable},
+			value:    []string{"a", "b"},
+			expected: "a\nb\n",
+		},
+		"table of scalar": {
+			format:   OutputFormat{Name: OutputFormatTable},
+			value:    42,
+			expected: "42\n",
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, (&Output{Format: test.format, Writer: &buf}).Render(test.value))
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+
+	t.Run("invalid template", func(t *testing.T) {
+		var buf bytes.Buffer
+		output := &Output{Format: OutputFormat{Name: OutputFormatTemplate, Template: "{{"}, Writer: &buf}
+		assert.Error(t, output.Render(items))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		var buf bytes.Buffer
+		output := &Output{Format: OutputFormat{Name: "xml"}, Writer: &buf}
+		assert.Error(t, output.Render(items))
+	})
+}
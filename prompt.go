@@ -0,0 +1,131 @@
+package clix
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrPromptAttemptsExceeded is returned when no valid answer is provided in the allowed attempts.
+var ErrPromptAttemptsExceeded = errors.New("too many invalid answers")
+
+// Prompter asks questions to the user, reading answers from the input stream
+// and writing questions to the error stream, keeping the output stream clean.
+type Prompter struct {
+	in           *bufio.Reader
+	out          io.Writer
+	readPassword func() (string, error)
+	maxAttempts  int
+}
+
+// NewPrompter creates a new prompter on the provided streams, giving up
+// after the provided number of invalid answers. The password echo is
+// disabled only if the input stream is a terminal.
+func NewPrompter(streams IO, maxAttempts int) *Prompter {
+	p := &Prompter{
+		in:          bufio.NewReader(streams.In),
+		out:         streams.Err,
+		maxAttempts: maxAttempts,
+	}
+
+	p.readPassword = p.readLine
+	if f, isFile := streams.In.(interface{ Fd() uintptr }); isFile && streams.IsInTerminal() {
+		p.readPassword = func() (string, error) {
+			password, err := term.ReadPassword(int(f.Fd()))
+			fmt.Fprintln(p.out) // nolint: errcheck, gosec
+			return string(password), err
+		}
+	}
+
+	return p
+}
+
+// Text asks for a free text answer, validated by the optional validation function.
+func (p *Prompter) Text(label string, validate func(string) error) (string, error) {
+	return p.ask(label+": ", p.readLine, validate)
+}
+
+// Password asks for a secret answer, not echoed when the input is a terminal.
+func (p *Prompter) Password(label string, validate func(string) error) (string, error) {
+	return p.ask(label+": ", p.readPassword, validate)
+}
+
+// Select asks to choose one of the provided options, by value or by number.
+func (p *Prompter) Select(label string, options []string) (string, error) {
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option) // nolint: errcheck, gosec
+	}
+
+	var selected string
+	_, err := p.ask(label+" [1-"+strconv.Itoa(len(options))+"]: ", p.readLine, func(answer string) error {
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(options) {
+			selected = options[i-1]
+			return nil
+		}
+		for _, option := range options {
+			if answer == option {
+				selected = option
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of the options", answer)
+	})
+	return selected, err
+}
+
+// Confirm asks a yes or no question, an empty answer selects the default one.
+func (p *Prompter) Confirm(label string, defaultAnswer bool) (bool, error) {
+	choices := "y/N"
+	if defaultAnswer {
+		choices = "Y/n"
+	}
+
+	confirmed := defaultAnswer
+	_, err := p.ask(label+" ["+choices+"]: ", p.readLine, func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "":
+		case "y", "yes":
+			confirmed = true
+		case "n", "no":
+			confirmed = false
+		default:
+			return errors.New("answer with yes or no")
+		}
+		return nil
+	})
+	return confirmed, err
+}
+
+func (p *Prompter) ask(question string, read func() (string, error), validate func(string) error) (string, error) {
+	for attempt := 0; attempt < p.maxAttempts; attempt++ {
+		fmt.Fprint(p.out, question) // nolint: errcheck, gosec
+
+		answer, err := read()
+		if err != nil {
+			return "", fmt.Errorf("unable to read answer: %w", err)
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "invalid answer: %v\n", err) // nolint: errcheck, gosec
+			continue
+		}
+		return answer, nil
+	}
+	return "", ErrPromptAttemptsExceeded
+}
+
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,147 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ctxKeyPrompt           ctxKey = "prompt"
+	annotationFlagPassword        = "clix_prompt_password"
+)
+
+// WithPrompt makes the command and its subcommands prompt for missing required
+// positional arguments and flags, instead of failing. Prompting only happens
+// when the input stream is a terminal, scripts still fail fast.
+// Required flags are the ones declared with FlagsRequired.
+func WithPrompt(cbf CommandBuilderFunc, opts ...PromptCommandOption) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		o := defaultPromptCommandOptions()
+		for _, opt := range opts {
+			opt(o)
+		}
+
+		ctx = context.WithValue(ctx, ctxKeyPrompt, o)
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+		return cmd, ctx, nil
+	}
+}
+
+// PromptFlagAsPassword makes the answer to the flag prompt not echoed.
+func PromptFlagAsPassword(flags *pflag.FlagSet, name string) error {
+	return flags.SetAnnotation(name, annotationFlagPassword, []string{"true"})
+}
+
+func promptMissingInputs(ctx context.Context, c *cobra.Command, inv *Invocation) error {
+	o, enabled := ctx.Value(ctxKeyPrompt).(*promptCommandOptions)
+	if !enabled || !o.isInteractive(inv.IO) {
+		return nil
+	}
+
+	p := NewPrompter(inv.IO, o.maxAttempts)
+	if err := promptMissingArgs(p, c, inv); err != nil {
+		return err
+	}
+	return promptMissingFlags(p, c, inv)
+}
+
+func promptMissingArgs(p *Prompter, c *cobra.Command, inv *Invocation) error {
+	declared, _, err := argsFromCommand(c)
+	if err != nil || len(inv.Args) >= len(declared) {
+		return err
+	}
+
+	for _, arg := range declared[len(inv.Args):] {
+		if !arg.Required {
+			break
+		}
+
+		var answer string
+		if arg.Type == ArgTypeEnum {
+			answer, err = p.Select(promptLabel(arg.Name, arg.Usage), arg.Enum)
+		} else {
+			answer, err = p.Text(promptLabel(arg.Name, arg.Usage), func(answer string) error {
+				if answer == "" {
+					return errors.New("a value is required")
+				}
+				_, err := arg.convert(answer)
+				return err
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("unable to prompt for argument %s: %w", arg.Name, err)
+		}
+
+		inv.Args = append(inv.Args, answer)
+	}
+	return nil
+}
+
+func promptMissingFlags(p *Prompter, c *cobra.Command, inv *Invocation) error {
+	constraints, err := flagConstraintsFromCommand(c)
+	if err != nil {
+		return err
+	}
+
+	for _, constraint := range constraints {
+		if constraint.Kind != FlagConstraintRequired {
+			continue
+		}
+		for _, name := range constraint.Flags {
+			flag := c.Flags().Lookup(name)
+			if flag == nil || flag.Changed {
+				continue
+			}
+			if err := promptFlag(p, c.Flags(), flag); err != nil {
+				return fmt.Errorf("unable to prompt for flag --%s: %w", name, err)
+			}
+			inv.ChangedFlags[name] = flag.Value.String()
+		}
+	}
+	return nil
+}
+
+func promptFlag(p *Prompter, flags *pflag.FlagSet, flag *pflag.Flag) error {
+	label := promptLabel("--"+flag.Name, flag.Usage)
+
+	if flag.Value.Type() == "bool" {
+		confirmed, err := p.Confirm(label, false)
+		if err != nil {
+			return err
+		}
+		return flags.Set(flag.Name, strconv.FormatBool(confirmed))
+	}
+
+	ask := p.Text
+	if _, isPassword := flag.Annotations[annotationFlagPassword]; isPassword || isSecretFlag(flag) {
+		ask = p.Password
+	}
+	if secret, isSecret := flag.Value.(*Secret); isSecret {
+		secret.literal = true
+		defer func() { secret.literal = false }()
+	}
+
+	_, err := ask(label, func(answer string) error {
+		if answer == "" {
+			return errors.New("a value is required")
+		}
+		return flags.Set(flag.Name, answer)
+	})
+	return err
+}
+
+func promptLabel(name string, usage string) string {
+	if usage == "" {
+		return name
+	}
+	return name + " (" + usage + ")"
+}
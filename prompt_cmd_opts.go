@@ -0,0 +1,22 @@
+package clix
+
+type promptCommandOptions struct {
+	maxAttempts   int
+	isInteractive func(streams IO) bool
+}
+
+func defaultPromptCommandOptions() *promptCommandOptions {
+	return &promptCommandOptions{
+		maxAttempts:   3,
+		isInteractive: IO.IsInTerminal,
+	}
+}
+
+// PromptCommandOption defines the signature of an option applier.
+type PromptCommandOption func(o *promptCommandOptions)
+
+// PromptWithMaxAttempts sets the number of invalid answers
+// accepted before giving up on a prompt.
+func PromptWithMaxAttempts(maxAttempts int) PromptCommandOption {
+	return func(o *promptCommandOptions) { o.maxAttempts = maxAttempts }
+}
@@ -0,0 +1,20 @@
+package clix
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_defaultPromptCommandOptions(t *testing.T) {
+	o := defaultPromptCommandOptions()
+	assert.Equal(t, 3, o.maxAttempts)
+	assert.False(t, o.isInteractive(IO{In: os.Stdin}), "tests are not run in a terminal")
+}
+
+func Test_PromptWithMaxAttempts(t *testing.T) {
+	var o promptCommandOptions
+	PromptWithMaxAttempts(5)(&o)
+	assert.Equal(t, 5, o.maxAttempts)
+}
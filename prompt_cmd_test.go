@@ -0,0 +1,88 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func promptWithInteractive(interactive bool) PromptCommandOption {
+	return func(o *promptCommandOptions) { o.isInteractive = func(IO) bool { return interactive } }
+}
+
+func Test_WithPrompt(t *testing.T) {
+	type result struct {
+		values   ArgValues
+		token    string
+		force    bool
+		password string
+	}
+
+	newCLI := func(res *result, interactive bool) *CLI {
+		deploy := testInvocationCommand("deploy", func(_ context.Context, inv Invocation) error {
+			res.values = inv.Values
+			return nil
+		}, func(cmd *cobra.Command) error {
+			cmd.Flags().StringVar(&res.token, "token", "", "api token")
+			cmd.Flags().StringVar(&res.password, "password", "", "")
+			cmd.Flags().BoolVar(&res.force, "force", false, "")
+			return PromptFlagAsPassword(cmd.Flags(), "password")
+		})
+
+		return Command(WithPrompt(testCommand("app", nil), PromptWithMaxAttempts(2), promptWithInteractive(interactive))).
+			SubCommand(WithFlagConstraints(WithArgs(deploy,
+				Arg{Name: "env", Type: ArgTypeEnum, Enum: []string{"dev", "prod"}, Required: true},
+				Arg{Name: "replicas", Type: ArgTypeInt, Required: true, Usage: "number of replicas"},
+				Arg{Name: "region"},
+			), FlagsRequired("token", "force", "password")))
+	}
+
+	t.Run("missing inputs are prompted", func(t *testing.T) {
+		var (
+			res    result
+			stderr strings.Builder
+		)
+		err := newCLI(&res, true).Exec(context.Background(), []string{"deploy"}, ExecWithIO(IO{
+			In:  strings.NewReader("2\nmany\n3\nabc\ny\nsecret\n"),
+			Out: ioutil.Discard,
+			Err: &stderr,
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, ArgValues{"env": "prod", "replicas": 3}, res.values)
+		assert.Equal(t, "abc", res.token)
+		assert.True(t, res.force)
+		assert.Equal(t, "secret", res.password)
+		assert.Contains(t, stderr.String(), "replicas (number of replicas): invalid answer")
+		assert.Contains(t, stderr.String(), "--token (api token): ")
+	})
+
+	t.Run("provided inputs are not prompted", func(t *testing.T) {
+		var res result
+		err := newCLI(&res, true).Exec(context.Background(), []string{
+			"deploy", "dev", "1", "--token", "abc", "--force", "--password", "secret",
+		}, ExecWithIO(IO{In: strings.NewReader(""), Out: ioutil.Discard, Err: ioutil.Discard}))
+		require.NoError(t, err)
+		assert.Equal(t, ArgValues{"env": "dev", "replicas": 1}, res.values)
+	})
+
+	t.Run("prompt failed", func(t *testing.T) {
+		var res result
+		err := newCLI(&res, true).Exec(context.Background(), []string{"deploy", "dev"},
+			ExecWithIO(IO{In: strings.NewReader("a\nb\n"), Out: ioutil.Discard, Err: ioutil.Discard}))
+		assert.Error(t, err)
+	})
+
+	t.Run("non interactive execution fails fast", func(t *testing.T) {
+		var res result
+		err := newCLI(&res, false).Exec(context.Background(), []string{"deploy"},
+			ExecWithIO(IO{In: strings.NewReader("prod\n3\n"), Out: ioutil.Discard, Err: ioutil.Discard}))
+		var usageErr *UsageError
+		assert.True(t, errors.As(err, &usageErr))
+	})
+}
@@ -0,0 +1,121 @@
+package clix
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrompter(input string) (*Prompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return NewPrompter(IO{In: strings.NewReader(input), Err: &out}, 2), &out
+}
+
+func TestPrompter_Text(t *testing.T) {
+	t.Run("answer is returned", func(t *testing.T) {
+		p, out := newTestPrompter("hello\n")
+		answer, err := p.Text("name", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", answer)
+		assert.Equal(t, "name: ", out.String())
+	})
+
+	t.Run("invalid answers are asked again", func(t *testing.T) {
+		p, out := newTestPrompter("boum\nhello\n")
+		answer, err := p.Text("name", func(answer string) error {
+			if answer == "boum" {
+				return errors.New("no boum")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", answer)
+		assert.Equal(t, "name: invalid answer: no boum\nname: ", out.String())
+	})
+
+	t.Run("too many invalid answers", func(t *testing.T) {
+		p, _ := newTestPrompter("a\nb\nc\n")
+		_, err := p.Text("name", func(string) error { return errors.New("boum") })
+		assert.True(t, errors.Is(err, ErrPromptAttemptsExceeded))
+	})
+
+	t.Run("last line without new line", func(t *testing.T) {
+		p, _ := newTestPrompter("hello")
+		answer, err := p.Text("name", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", answer)
+	})
+
+	t.Run("nothing to read", func(t *testing.T) {
+		p, _ := newTestPrompter("")
+		_, err := p.Text("name", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestPrompter_Password(t *testing.T) {
+	p, out := newTestPrompter("secret\n")
+	answer, err := p.Password("password", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", answer)
+	assert.Equal(t, "password: ", out.String())
+}
+
+func TestPrompter_Select(t *testing.T) {
+	for name, test := range map[string]struct {
+		input       string
+		expected    string
+		expectedErr bool
+	}{
+		"by number":        {input: "2\n", expected: "prod"},
+		"by value":         {input: "dev\n", expected: "dev"},
+		"out of range":     {input: "3\n1\n", expected: "dev"},
+		"unknown value":    {input: "staging\nqa\n", expectedErr: true},
+		"nothing selected": {input: "", expectedErr: true},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p, out := newTestPrompter(test.input)
+			selected, err := p.Select("env", []string{"dev", "prod"})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, selected)
+			assert.True(t, strings.HasPrefix(out.String(), "  1) dev\n  2) prod\nenv [1-2]: "), out.String())
+		})
+	}
+}
+
+func TestPrompter_Confirm(t *testing.T) {
+	for name, test := range map[string]struct {
+		input         string
+		defaultAnswer bool
+		expected      bool
+		expectedErr   bool
+	}{
+		"yes":                 {input: "y\n", expected: true},
+		"no":                  {input: "No\n", defaultAnswer: true, expected: false},
+		"default to no":       {input: "\n", expected: false},
+		"default to yes":      {input: "\n", defaultAnswer: true, expected: true},
+		"invalid then yes":    {input: "maybe\nyes\n", expected: true},
+		"only invalid answer": {input: "maybe\nmaybe\n", expectedErr: true},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			p, _ := newTestPrompter(test.input)
+			confirmed, err := p.Confirm("sure", test.defaultAnswer)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, confirmed)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// PanicError is returned when a handler panicked.
+type PanicError struct {
+	// Value is the value the handler panicked with.
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+	// CrashReport is the path of the written crash report, if any.
+	CrashReport string
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("handler panicked: %v", e.Value) }
+
+// ExitCode implements ExitCoder.
+func (e *PanicError) ExitCode() int { return ExitCodePanic }
+
+// Recover creates a middleware recovering handlers panics, logged with their
+// stack trace with the logger from the context, and returned as PanicError.
+// A default recover middleware is always applied, as the outermost one.
+func Recover(opts ...RecoverOption) Middleware {
+	o := new(recoverOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, args []string, dashedArgs []string) (err error) {
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+
+				panicErr := &PanicError{Value: value, Stack: debug.Stack()}
+				fields := map[string]interface{}{
+					"panic": fmt.Sprint(value),
+					"stack": string(panicErr.Stack),
+				}
+
+				log := LoggerFromContext(ctx)
+				if o.crashReportDir != "" {
+					path, reportErr := writeCrashReport(ctx, o.crashReportDir, panicErr)
+					if reportErr != nil && log != nil {
+						log.WithError(reportErr).Error("unable to write crash report")
+					}
+					panicErr.CrashReport = path
+					fields["crash_report"] = path
+				}
+				if log != nil {
+					log.WithFields(fields).Error("handler panicked")
+				}
+
+				err = panicErr
+			}()
+
+			return next.Handle(ctx, args, dashedArgs)
+		})
+	}
+}
+
+type crashReport struct {
+	Time      time.Time         `json:"time"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Flags     map[string]string `json:"flags,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Commit    string            `json:"commit,omitempty"`
+	GoVersion string            `json:"go_version"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	Panic     string            `json:"panic"`
+	Stack     string            `json:"stack"`
+}
+
+func writeCrashReport(ctx context.Context, dir string, panicErr *PanicError) (string, error) {
+	app, _ := AppFromContext(ctx)
+	version := NewVersionInfo(app)
+
+	report := crashReport{
+		Time:      time.Now().UTC(),
+		Version:   version.Version,
+		Commit:    version.Commit,
+		GoVersion: version.GoVersion,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Panic:     fmt.Sprint(panicErr.Value),
+		Stack:     string(panicErr.Stack),
+	}
+
+	if inv, hasInvocation := InvocationFromContext(ctx); hasInvocation {
+		report.Command = inv.CommandPath
+		report.Args = maskSensitiveArgs(inv.flags, inv.RawArgs)
+		report.Flags = make(map[string]string, len(inv.ChangedFlags))
+		for name, value := range inv.ChangedFlags {
+			if isSensitiveFlag(lookupFlag(inv.flags, "--"+name)) {
+				value = maskedValue
+			}
+			report.Flags[name] = value
+		}
+	}
+
+	raw, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize crash report: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%d.json", report.Time.Format("20060102T150405"), os.Getpid()))
+	if err := ioutil.WriteFile(path, raw, 0o600); err != nil {
+		return "", fmt.Errorf("unable to write crash report: %w", err)
+	}
+	return path, nil
+}
+
+const maskedValue = "****"
+
+// MaskFlagValue returns the flag value to report, in traces or crash reports,
+// masked if the flag is sensitive.
+func MaskFlagValue(flag *pflag.Flag) string {
+	if isSensitiveFlag(flag) {
+		return maskedValue
+	}
+	return flag.Value.String()
+}
+
+// isSensitiveFlag returns whenever the flag value should be masked: flags explicitly
+// marked as sensitive are Secret flags and flags prompted as passwords.
+// The flag may be nil when unknown.
+func isSensitiveFlag(flag *pflag.Flag) bool {
+	if flag == nil {
+		return false
+	}
+	_, isPassword := flag.Annotations[annotationFlagPassword]
+	return isPassword || isSecretFlag(flag)
+}
+
+// lookupFlag returns the flag named as on the command line, with its dashes:
+// --name for a flag name, -n for a shorthand.
+func lookupFlag(flags *pflag.FlagSet, name string) *pflag.Flag {
+	switch {
+	case flags == nil:
+		return nil
+	case strings.HasPrefix(name, "--"):
+		return flags.Lookup(name[2:])
+	case strings.HasPrefix(name, "-") && len(name) == 2:
+		return flags.ShorthandLookup(name[1:])
+	}
+	return nil
+}
+
+// maskSensitiveArgs returns a copy of the arguments where sensitive flags values are masked.
+func maskSensitiveArgs(flags *pflag.FlagSet, args []string) []string {
+	masked := append([]string(nil), args...)
+
+	for i := 0; i < len(masked); i++ {
+		arg := masked[i]
+		switch {
+		case arg == "--":
+			return masked
+		case strings.HasPrefix(arg, "--"):
+			name := arg[2:]
+			if value := strings.Index(name, "="); value >= 0 {
+				if isSensitiveFlag(lookupFlag(flags, arg[:2+value])) {
+					masked[i] = arg[:2+value+1] + maskedValue
+				}
+				continue
+			}
+			if flag := lookupFlag(flags, arg); isSensitiveFlag(flag) && flag.NoOptDefVal == "" && i+1 < len(masked) {
+				masked[i+1] = maskedValue
+				i++
+			}
+		case len(arg) > 1 && arg[0] == '-':
+			i += maskSensitiveShorthands(flags, masked, i)
+		}
+	}
+
+	return masked
+}
+
+// maskSensitiveShorthands masks the value of the sensitive flag of the shorthands argument
+// at the provided index, like -p=value, -pvalue, -vp value, and returns the number of
+// following arguments consumed as value. Shorthands can be combined, the first
+// one expecting a value takes the rest of the argument, or the next argument.
+func maskSensitiveShorthands(flags *pflag.FlagSet, args []string, i int) int {
+	arg := args[i]
+	for j := 1; j < len(arg); j++ {
+		flag := lookupFlag(flags, "-"+arg[j:j+1])
+		if flag == nil {
+			return 0
+		}
+		if flag.NoOptDefVal != "" { // like booleans, no value is expected
+			continue
+		}
+		if !isSensitiveFlag(flag) {
+			return 0
+		}
+
+		switch {
+		case j+1 < len(arg) && arg[j+1] == '=':
+			args[i] = arg[:j+2] + maskedValue
+		case j+1 < len(arg):
+			args[i] = arg[:j+1] + maskedValue
+		case i+1 < len(args):
+			args[i+1] = maskedValue
+			return 1
+		}
+		return 0
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package clix
+
+type recoverOptions struct {
+	crashReportDir string
+}
+
+// RecoverOption defines the signature of an option applier.
+type RecoverOption func(o *recoverOptions)
+
+// RecoverWithCrashReportDir writes a crash report in the provided directory when
+// a handler panics. The report contains the arguments, sensitive flags values
+// masked, the application version, and the Go runtime details.
+func RecoverWithCrashReportDir(dir string) RecoverOption {
+	return func(o *recoverOptions) { o.crashReportDir = dir }
+}
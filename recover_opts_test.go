@@ -0,0 +1,13 @@
+package clix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RecoverWithCrashReportDir(t *testing.T) {
+	var o recoverOptions
+	RecoverWithCrashReportDir("/tmp")(&o)
+	assert.Equal(t, "/tmp", o.crashReportDir)
+}
@@ -0,0 +1,199 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Recover(t *testing.T) {
+	newCLI := func(log *logger.InMemory, handle HandlerFunc) *CLI {
+		return Command(WithApp(WithLogger(testCommand("app", handle, func(cmd *cobra.Command) error {
+			cmd.Flags().String("db-password", "", "")
+			cmd.Flags().String("name", "", "")
+			return PromptFlagAsPassword(cmd.Flags(), "db-password")
+		}), inMemoryLoggerCreateFunc(log)), AppWithVersion("1.2.3")))
+	}
+	panicking := HandlerFunc(func(context.Context, []string, []string) error { panic("boum") })
+	silent := ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard})
+
+	t.Run("panics are recovered by default", func(t *testing.T) {
+		log := logger.NewInMemory(logger.LevelInfo)
+		err := newCLI(log, panicking).Exec(context.Background(), []string{}, silent)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(err, &panicErr))
+		assert.Equal(t, "boum", panicErr.Value)
+		assert.Contains(t, string(panicErr.Stack), "recover_test.go")
+		assert.Empty(t, panicErr.CrashReport)
+		assert.Equal(t, ExitCodePanic, ExitCode(err))
+
+		require.Len(t, log.Entries, 1)
+		assert.Equal(t, logger.LevelError, log.Entries[0].Level)
+		assert.Equal(t, "boum", log.Entries[0].Fields["panic"])
+		assert.Contains(t, log.Entries[0].Fields["stack"], "recover_test.go")
+	})
+
+	t.Run("crash report is written", func(t *testing.T) {
+		dir := t.TempDir()
+		log := logger.NewInMemory(logger.LevelInfo)
+		err := newCLI(log, panicking).Use(Recover(RecoverWithCrashReportDir(dir))).Exec(context.Background(), []string{
+			"--db-password", "secret", "--name=joe",
+		}, silent)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(err, &panicErr))
+		require.NotEmpty(t, panicErr.CrashReport)
+		assert.Equal(t, dir, filepath.Dir(panicErr.CrashReport))
+		require.Len(t, log.Entries, 1)
+		assert.Equal(t, panicErr.CrashReport, log.Entries[0].Fields["crash_report"])
+
+		raw, err := ioutil.ReadFile(panicErr.CrashReport)
+		require.NoError(t, err)
+		var report crashReport
+		require.NoError(t, json.Unmarshal(raw, &report))
+		assert.Equal(t, "app", report.Command)
+		assert.Equal(t, []string{"--db-password", "****", "--name=joe"}, report.Args)
+		assert.Equal(t, map[string]string{"db-password": "****", "name": "joe"}, report.Flags)
+		assert.Equal(t, "1.2.3", report.Version)
+		assert.Equal(t, "boum", report.Panic)
+		assert.NotEmpty(t, report.GoVersion)
+		assert.NotEmpty(t, report.Stack)
+	})
+
+	t.Run("crash report can't be written", func(t *testing.T) {
+		log := logger.NewInMemory(logger.LevelInfo)
+		err := newCLI(log, panicking).Use(Recover(RecoverWithCrashReportDir(filepath.Join(t.TempDir(), "404")))).
+			Exec(context.Background(), []string{}, silent)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(err, &panicErr))
+		assert.Empty(t, panicErr.CrashReport)
+		assert.Len(t, log.Entries, 2)
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		log := logger.NewInMemory(logger.LevelInfo)
+		err := newCLI(log, func(context.Context, []string, []string) error {
+			return errors.New("boum")
+		}).Exec(context.Background(), []string{}, silent)
+		assert.EqualError(t, err, "boum")
+		assert.Empty(t, log.Entries)
+	})
+
+	t.Run("without logger", func(t *testing.T) {
+		err := Recover()(panicking).Handle(context.Background(), nil, nil)
+		var panicErr *PanicError
+		assert.True(t, errors.As(err, &panicErr))
+	})
+}
+
+func Test_maskSensitiveArgs(t *testing.T) {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.StringP("password", "p", "", "")
+	require.NoError(t, PromptFlagAsPassword(flags, "password"))
+	flags.BoolP("verbose", "v", false, "")
+	flags.Bool("token-refresh", false, "")
+	flags.String("pin", "", "")
+	require.NoError(t, PromptFlagAsPassword(flags, "pin"))
+	flags.StringP("name", "n", "", "")
+	flags.String("api-key", "", "")
+	var key Secret
+	SecretVar(flags, &key, "k", "", "")
+	flags.StringP("kind", "k", "", "")
+
+	for name, test := range map[string]struct {
+		flags    *pflag.FlagSet
+		args     []string
+		expected []string
+	}{
+		"separated value": {
+			flags:    flags,
+			args:     []string{"--password", "secret", "--name", "joe"},
+			expected: []string{"--password", "****", "--name", "joe"},
+		},
+		"attached value": {
+			flags:    flags,
+			args:     []string{"--password=secret", "-p=secret"},
+			expected: []string{"--password=****", "-p=****"},
+		},
+		"shorthand": {
+			flags:    flags,
+			args:     []string{"-p", "secret", "arg"},
+			expected: []string{"-p", "****", "arg"},
+		},
+		"shorthand with attached value": {
+			flags:    flags,
+			args:     []string{"-psecret", "-nsecret"},
+			expected: []string{"-p****", "-nsecret"},
+		},
+		"combined shorthands": {
+			flags:    flags,
+			args:     []string{"-vpsecret", "-vp", "secret", "-vn", "joe"},
+			expected: []string{"-vp****", "-vp", "****", "-vn", "joe"},
+		},
+		"one letter flag name": {
+			flags:    flags,
+			args:     []string{"--k=secret", "--k", "secret", "-k", "value"},
+			expected: []string{"--k=****", "--k", "****", "-k", "value"},
+		},
+		"flag not explicitly sensitive": {
+			flags:    flags,
+			args:     []string{"--api-key", "value"},
+			expected: []string{"--api-key", "value"},
+		},
+		"flag without value": {
+			flags:    flags,
+			args:     []string{"--token-refresh", "arg"},
+			expected: []string{"--token-refresh", "arg"},
+		},
+		"annotated flag": {
+			flags:    flags,
+			args:     []string{"--pin", "1234"},
+			expected: []string{"--pin", "****"},
+		},
+		"after double dash": {
+			flags:    flags,
+			args:     []string{"--", "--password", "secret"},
+			expected: []string{"--", "--password", "secret"},
+		},
+		"unknown flags": {
+			args:     []string{"--api-key", "secret", "--other", "value", "-"},
+			expected: []string{"--api-key", "secret", "--other", "value", "-"},
+		},
+		"missing value": {
+			flags:    flags,
+			args:     []string{"--password"},
+			expected: []string{"--password"},
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			args := append([]string(nil), test.args...)
+			assert.Equal(t, test.expected, maskSensitiveArgs(test.flags, args))
+			assert.Equal(t, test.args, args, "provided args are left untouched")
+		})
+	}
+}
+
+func Test_lookupFlag(t *testing.T) {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.String("k", "", "")
+	flags.StringP("kind", "k", "", "")
+
+	assert.Equal(t, "k", lookupFlag(flags, "--k").Name)
+	assert.Equal(t, "kind", lookupFlag(flags, "-k").Name)
+	assert.Equal(t, "kind", lookupFlag(flags, "--kind").Name)
+	assert.Nil(t, lookupFlag(flags, "k"))
+	assert.Nil(t, lookupFlag(flags, "-kind"))
+	assert.Nil(t, lookupFlag(nil, "--k"))
+}
@@ -0,0 +1,166 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// SchemaVersion is the version of the command tree schema, it changes
+// whenever a field is removed or its meaning changes.
+const SchemaVersion = "1"
+
+// Schema is a machine readable description of a command tree.
+type Schema struct {
+	SchemaVersion string        `json:"schema_version"`
+	Command       CommandSchema `json:"command"`
+}
+
+// CommandSchema describes a command and its subcommands.
+type CommandSchema struct {
+	Name        string           `json:"name"`
+	Path        string           `json:"path"`
+	Aliases     []string         `json:"aliases,omitempty"`
+	Usage       string           `json:"usage"`
+	Short       string           `json:"short,omitempty"`
+	Long        string           `json:"long,omitempty"`
+	Example     string           `json:"example,omitempty"`
+	Runnable    bool             `json:"runnable"`
+	Hidden      bool             `json:"hidden"`
+	Deprecated  string           `json:"deprecated,omitempty"`
+	Args        []Arg            `json:"args,omitempty"`
+	Flags       []FlagSchema     `json:"flags,omitempty"`
+	Constraints []FlagConstraint `json:"flag_constraints,omitempty"`
+	Commands    []CommandSchema  `json:"commands,omitempty"`
+}
+
+// FlagSchema describes a flag declared by a command. Persistent
+// flags are described only once, on the command declaring them.
+type FlagSchema struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default"`
+	Usage      string `json:"usage,omitempty"`
+	Env        string `json:"env,omitempty"`
+	Persistent bool   `json:"persistent"`
+	Hidden     bool   `json:"hidden"`
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// NewSchema builds the command line interface and describes its whole command tree.
+func NewSchema(ctx context.Context, cli *CLI) (*Schema, error) {
+	cmd, _, err := cli.Build()(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build command: %w", err)
+	}
+	return newSchema(cmd)
+}
+
+// SchemaCommand builds a hidden schema command printing
+// the schema of the whole command tree as json.
+func SchemaCommand(ctx context.Context) (*cobra.Command, context.Context, error) {
+	cmd := &cobra.Command{
+		Use:    "schema",
+		Short:  "Print the command tree schema as json",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+	}
+	cmd.RunE = ExecHandler(ctx, func(func()) (Handler, error) {
+		return HandlerFunc(func(ctx context.Context, _, _ []string) error {
+			schema, err := newSchema(cmd.Root())
+			if err != nil {
+				return err
+			}
+			return schema.write(IOFromContext(ctx).Out)
+		}), nil
+	})
+
+	return cmd, ctx, nil
+}
+
+func newSchema(root *cobra.Command) (*Schema, error) {
+	command, err := newCommandSchema(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{SchemaVersion: SchemaVersion, Command: *command}, nil
+}
+
+func newCommandSchema(cmd *cobra.Command) (*CommandSchema, error) {
+	args, _, err := argsFromCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := flagConstraintsFromCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	command := CommandSchema{
+		Name:        cmd.Name(),
+		Path:        cmd.CommandPath(),
+		Aliases:     cmd.Aliases,
+		Usage:       cmd.UseLine(),
+		Short:       cmd.Short,
+		Long:        cmd.Long,
+		Example:     cmd.Example,
+		Runnable:    cmd.Runnable(),
+		Hidden:      cmd.Hidden,
+		Deprecated:  cmd.Deprecated,
+		Args:        args,
+		Constraints: constraints,
+	}
+
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		command.Flags = append(command.Flags, newFlagSchema(flag, false))
+	})
+	cmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		command.Flags = append(command.Flags, newFlagSchema(flag, true))
+	})
+
+	for _, sub := range cmd.Commands() {
+		if isDefaultHelpCommand(sub) {
+			continue
+		}
+		subcommand, err := newCommandSchema(sub)
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe %q: %w", sub.CommandPath(), err)
+		}
+		command.Commands = append(command.Commands, *subcommand)
+	}
+
+	return &command, nil
+}
+
+// isDefaultHelpCommand returns whether the command is the help command cobra adds
+// on execution, the only runnable command unavailable while not hidden nor deprecated.
+func isDefaultHelpCommand(cmd *cobra.Command) bool {
+	return cmd.Runnable() && !cmd.Hidden && cmd.Deprecated == "" && !cmd.IsAvailableCommand()
+}
+
+func newFlagSchema(flag *pflag.Flag, persistent bool) FlagSchema {
+	env, _ := flagEnv(flag)
+	return FlagSchema{
+		Name:       flag.Name,
+		Shorthand:  flag.Shorthand,
+		Type:       flag.Value.Type(),
+		Default:    flag.DefValue,
+		Usage:      flag.Usage,
+		Env:        env,
+		Persistent: persistent,
+		Hidden:     flag.Hidden,
+		Deprecated: flag.Deprecated,
+	}
+}
+
+func (s *Schema) write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
@@ -0,0 +1,86 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewSchema(t *testing.T) {
+	t.Run("whole tree is described", func(t *testing.T) {
+		schema, err := NewSchema(context.Background(), newDocsTestCLI())
+		require.NoError(t, err)
+
+		assert.Equal(t, SchemaVersion, schema.SchemaVersion)
+		assert.Equal(t, "app", schema.Command.Path)
+		assert.False(t, schema.Command.Runnable)
+		assert.Equal(t, []FlagSchema{{
+			Name:       "output",
+			Shorthand:  "o",
+			Type:       "format",
+			Default:    "table",
+			Usage:      "format to print results with, one of json|yaml|table|template=<go-template>",
+			Persistent: true,
+		}}, schema.Command.Flags)
+
+		require.Len(t, schema.Command.Commands, 2)
+		deploy, secret := schema.Command.Commands[0], schema.Command.Commands[1]
+
+		assert.Equal(t, CommandSchema{
+			Name:     "deploy",
+			Path:     "app deploy",
+			Usage:    "app deploy <env> [flags]",
+			Short:    "Deploy the app",
+			Long:     "Deploy the app to the provided environment.",
+			Example:  "app deploy prod --region eu",
+			Runnable: true,
+			Args: []Arg{
+				{Name: "env", Type: ArgTypeEnum, Enum: []string{"dev", "prod"}, Required: true, Usage: "target environment"},
+			},
+			Flags: []FlagSchema{
+				{Name: "force", Type: "bool", Default: "false", Usage: "skip checks"},
+				{Name: "region", Type: "string", Default: "us", Usage: "region to deploy to", Env: "APP_REGION"},
+			},
+			Constraints: []FlagConstraint{FlagRequires("force", "region")},
+		}, deploy)
+
+		assert.Equal(t, "app secret", secret.Path)
+		assert.True(t, secret.Hidden)
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		_, err := NewSchema(context.Background(), Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		}))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		_, err := NewSchema(context.Background(), Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{Use: "app"}, ctx, nil
+		}).SubCommand(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{Use: "sub", Annotations: map[string]string{annotationArgs: "{"}}, ctx, nil
+		}))
+		assert.Error(t, err)
+	})
+}
+
+func Test_SchemaCommand(t *testing.T) {
+	var out bytes.Buffer
+	err := newDocsTestCLI().SubCommand(SchemaCommand).Exec(context.Background(), []string{"schema"}, ExecWithIO(IO{Out: &out}))
+	require.NoError(t, err)
+
+	var schema Schema
+	require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+	assert.Equal(t, SchemaVersion, schema.SchemaVersion)
+	require.Len(t, schema.Command.Commands, 3)
+	assert.Equal(t, "app schema", schema.Command.Commands[1].Path)
+	assert.True(t, schema.Command.Commands[1].Hidden)
+	assert.Contains(t, out.String(), `"usage": "app deploy <env> [flags]"`)
+}
@@ -0,0 +1,159 @@
+package clix
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Secret is a flag value for sensitive values like passwords or tokens. It is always
+// displayed masked: in help defaults, logs, audit records, generated documentation or
+// serialized configurations. The value can be provided literally, read from a file
+// with "@path", or read from the standard input with "-"; a literal value starting
+// with "@" is escaped with "@@". Values read are limited to 1MiB, or to the size set
+// with ExecWithFlagFiles. Executed with Exec, the standard input is the command one.
+// Secret flags are prompted for without echo.
+type Secret struct {
+	value   string
+	set     bool
+	literal bool // prompted values are never read from files or standard input
+
+	stdin   func() io.Reader
+	maxSize int64
+}
+
+// NewSecret creates a secret holding the provided value.
+func NewSecret(value string) Secret { return Secret{value: value, set: value != ""} }
+
+// Value returns the unmasked value of the secret.
+func (s Secret) Value() string { return s.value }
+
+// IsSet returns whenever the secret holds a value.
+func (s Secret) IsSet() bool { return s.set }
+
+// String implements fmt.Stringer and pflag.Value, the value is masked.
+func (s Secret) String() string {
+	if !s.set {
+		return ""
+	}
+	return maskedValue
+}
+
+// GoString implements fmt.GoStringer, the value is masked.
+func (s Secret) GoString() string { return fmt.Sprintf("clix.Secret(%q)", s.String()) }
+
+// MarshalText implements encoding.TextMarshaler, the value is masked.
+func (s Secret) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// Set implements pflag.Value.
+func (s *Secret) Set(raw string) error {
+	var value string
+
+	switch {
+	case s.literal:
+		value = raw
+	case raw == "-":
+		stdin := io.Reader(os.Stdin)
+		if s.stdin != nil {
+			stdin = s.stdin()
+		}
+		content, err := readLimited(stdin, s.limit())
+		if err != nil {
+			return fmt.Errorf("unable to read secret from standard input: %w", err)
+		}
+		value = trimTrailingNewline(content)
+	case strings.HasPrefix(raw, "@@"):
+		value = raw[1:]
+	case strings.HasPrefix(raw, "@"):
+		content, err := readSecretFile(raw[1:], s.limit())
+		if err != nil {
+			return err
+		}
+		value = trimTrailingNewline(content)
+	default:
+		value = raw
+	}
+
+	s.value, s.set = value, true
+	return nil
+}
+
+// Type implements pflag.Value.
+func (s *Secret) Type() string { return "secret" }
+
+func (s *Secret) limit() int64 {
+	if s.maxSize > 0 {
+		return s.maxSize
+	}
+	return defaultMaxValueSize
+}
+
+func readSecretFile(path string, maxSize int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open secret file: %w", err)
+	}
+	defer file.Close() // nolint: errcheck, gosec
+
+	content, err := readLimited(file, maxSize)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret file %q: %w", path, err)
+	}
+	return content, nil
+}
+
+// SecretVar defines a secret flag with specified name, default value, and usage string.
+// The argument p points to a Secret variable in which to store the value of the flag.
+func SecretVar(flags *pflag.FlagSet, p *Secret, name string, value string, usage string) {
+	SecretVarP(flags, p, name, "", value, usage)
+}
+
+// SecretVarP is like SecretVar, but accepts a shorthand letter
+// that can be used after a single dash.
+func SecretVarP(flags *pflag.FlagSet, p *Secret, name, shorthand string, value string, usage string) {
+	*p = NewSecret(value)
+	flags.VarP(p, name, shorthand, usage)
+}
+
+// bindSecretFlags makes the secret flags of the command and its subcommands
+// read the standard input from the command, limiting the size of values read.
+func bindSecretFlags(cmd *cobra.Command, maxSize int64) {
+	stdin := cmd.InOrStdin
+	bind := func(flag *pflag.Flag) {
+		if secret, isSecret := flag.Value.(*Secret); isSecret {
+			secret.stdin, secret.maxSize = stdin, maxSize
+		}
+	}
+
+	cmd.PersistentFlags().VisitAll(bind)
+	cmd.LocalNonPersistentFlags().VisitAll(bind)
+	for _, sub := range cmd.Commands() {
+		bindSecretFlags(sub, maxSize)
+	}
+}
+
+// hasSecretFlags returns whether the command, or one of its subcommands, has a Secret flag.
+func hasSecretFlags(cmd *cobra.Command) bool {
+	var hasSecret bool
+	visit := func(flag *pflag.Flag) { hasSecret = hasSecret || isSecretFlag(flag) }
+
+	cmd.PersistentFlags().VisitAll(visit)
+	cmd.LocalNonPersistentFlags().VisitAll(visit)
+	for _, sub := range cmd.Commands() {
+		hasSecret = hasSecret || hasSecretFlags(sub)
+	}
+	return hasSecret
+}
+
+func isSecretFlag(flag *pflag.Flag) bool {
+	_, isSecret := flag.Value.(*Secret)
+	return isSecret
+}
+
+func trimTrailingNewline(s string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+}
@@ -0,0 +1,123 @@
+package clix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Secret_Set(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	for raw, expected := range map[string]string{
+		"literal":  "literal",
+		"@" + path: "from-file",
+		"-":        "from-stdin",
+		"@@at":     "@at",
+	} {
+		secret := Secret{stdin: func() io.Reader { return strings.NewReader("from-stdin\r\n") }}
+		require.NoError(t, secret.Set(raw), raw)
+		assert.Equal(t, expected, secret.Value(), raw)
+		assert.True(t, secret.IsSet(), raw)
+	}
+
+	var secret Secret
+	err := secret.Set("@" + filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+	assert.False(t, secret.IsSet())
+
+	secret = Secret{stdin: func() io.Reader { return strings.NewReader("too-long") }, maxSize: 4}
+	assert.Error(t, secret.Set("-"))
+	assert.Error(t, secret.Set("@"+path))
+	assert.False(t, secret.IsSet())
+}
+
+func Test_Secret_masked(t *testing.T) {
+	secret := NewSecret("s3cr3t")
+
+	assert.Equal(t, "****", secret.String())
+	assert.Equal(t, "****", fmt.Sprint(secret))
+	assert.Equal(t, `clix.Secret("****")`, fmt.Sprintf("%#v", secret))
+	assert.Equal(t, "{Token:****}", fmt.Sprintf("%+v", struct{ Token Secret }{secret}))
+
+	raw, err := json.Marshal(struct{ Token Secret }{secret})
+	require.NoError(t, err)
+	assert.Equal(t, `{"Token":"****"}`, string(raw))
+
+	assert.Empty(t, Secret{}.String())
+	assert.False(t, Secret{}.IsSet())
+}
+
+func Test_SecretVar(t *testing.T) {
+	var token, password Secret
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	SecretVar(flags, &token, "token", "default", "api token")
+	SecretVarP(flags, &password, "pass", "p", "", "")
+
+	assert.Equal(t, "default", token.Value())
+	assert.Contains(t, flags.FlagUsages(), "api token (default ****)")
+	assert.NotContains(t, flags.FlagUsages(), "default\"")
+
+	require.NoError(t, flags.Parse([]string{"--token", "s3cr3t", "-p", "passw0rd"}))
+	assert.Equal(t, "s3cr3t", token.Value())
+	assert.Equal(t, "passw0rd", password.Value())
+	assert.Equal(t, "****", MaskFlagValue(flags.Lookup("pass")))
+	assert.Equal(t, []string{"-p", "****"}, maskSensitiveArgs(flags, []string{"-p", "passw0rd"}))
+}
+
+func Test_Secret_invocation(t *testing.T) {
+	var (
+		secret Secret
+		inv    Invocation
+	)
+
+	cli := Command(WithPrompt(testInvocationCommand("app", recordInvocation(&inv), func(cmd *cobra.Command) error {
+		SecretVar(cmd.Flags(), &secret, "credentials", "", "")
+		return nil
+	}), promptWithInteractive(true)))
+
+	t.Run("changed flags are masked", func(t *testing.T) {
+		require.NoError(t, cli.Exec(context.Background(), []string{"--credentials", "s3cr3t"}))
+		assert.Equal(t, "s3cr3t", secret.Value())
+		assert.Equal(t, map[string]string{"credentials": "****"}, inv.ChangedFlags)
+	})
+
+	t.Run("standard input is the command one", func(t *testing.T) {
+		require.NoError(t, cli.Exec(context.Background(), []string{"--credentials", "-"},
+			ExecWithIO(IO{In: strings.NewReader("from-stdin\n"), Out: ioutil.Discard, Err: ioutil.Discard}),
+		))
+		assert.Equal(t, "from-stdin", secret.Value())
+
+		err := cli.Exec(context.Background(), []string{"--credentials", "-"},
+			ExecWithIO(IO{In: strings.NewReader("too-long"), Out: ioutil.Discard, Err: ioutil.Discard}),
+			ExecWithFlagFiles(FlagFileWithMaxSize(4)),
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("prompted values are literal", func(t *testing.T) {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		var prompted Secret
+		SecretVar(flags, &prompted, "token", "", "")
+
+		p := NewPrompter(IO{In: strings.NewReader("-\n"), Out: ioutil.Discard, Err: ioutil.Discard}, 1)
+		require.NoError(t, promptFlag(p, flags, flags.Lookup("token")))
+		assert.Equal(t, "-", prompted.Value())
+		assert.True(t, flags.Lookup("token").Changed)
+
+		require.NoError(t, prompted.Set("@@x"))
+		assert.Equal(t, "@x", prompted.Value())
+	})
+}
@@ -0,0 +1,64 @@
+package clix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const ctxKeyStepHooks ctxKey = "step-hooks"
+
+// Execution steps reported to step hooks.
+const (
+	// StepExec spans the whole execution of the command line interface.
+	StepExec = "exec"
+	// StepLoggerInit spans the logger creation, in WithLogger pre-run.
+	StepLoggerInit = "logger.init"
+	// StepHandler spans the command handler.
+	StepHandler = "handler"
+)
+
+// StepHook is called when an execution step starts. The returned context is used
+// during the step, it is handed to the handler for StepHandler. The returned function
+// is called when the step ends, with the error the step failed with, if any.
+type StepHook func(ctx context.Context, step string) (context.Context, func(err error))
+
+// WithStepHook reports the execution steps of the command
+// and its subcommands to the provided hook, to trace them for instance.
+func WithStepHook(cbf CommandBuilderFunc, hook StepHook) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		inherited := stepHooksFromContext(ctx)
+		hooks := make([]StepHook, 0, len(inherited)+1)
+		hooks = append(hooks, inherited...)
+		hooks = append(hooks, hook)
+		ctx = context.WithValue(ctx, ctxKeyStepHooks, hooks)
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+		return cmd, ctx, nil
+	}
+}
+
+func stepHooksFromContext(ctx context.Context) []StepHook {
+	hooks, _ := ctx.Value(ctxKeyStepHooks).([]StepHook)
+	return hooks
+}
+
+// startStep notifies the hooks from the context that the step started, and returns
+// the function to call when it ends, which notifies the hooks in the reverse order.
+func startStep(ctx context.Context, step string) (context.Context, func(err error)) {
+	hooks := stepHooksFromContext(ctx)
+	ends := make([]func(error), len(hooks))
+	for i, hook := range hooks {
+		ctx, ends[i] = hook(ctx, step)
+	}
+
+	return ctx, func(err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithStepHook(t *testing.T) {
+	type ctxKeyTest struct{}
+
+	var steps []string
+	hook := func(name string) StepHook {
+		return func(ctx context.Context, step string) (context.Context, func(error)) {
+			steps = append(steps, name+" start "+step)
+			return context.WithValue(ctx, ctxKeyTest{}, name), func(err error) {
+				end := name + " end " + step
+				if err != nil {
+					end += ": " + err.Error()
+				}
+				steps = append(steps, end)
+			}
+		}
+	}
+
+	newCLI := func(handle HandlerFunc) *CLI {
+		return Command(WithStepHook(testCommand("app", nil), hook("a"))).SubCommand(WithStepHook(testCommand("sub", handle), hook("b")))
+	}
+
+	t.Run("steps are reported to hooks", func(t *testing.T) {
+		steps = nil
+		var handledCtx context.Context
+		require.NoError(t, newCLI(func(ctx context.Context, _, _ []string) error {
+			handledCtx = ctx
+			return nil
+		}).Exec(context.Background(), []string{"sub"}))
+
+		assert.Equal(t, []string{
+			"a start exec",
+			"a start handler", "b start handler",
+			"b end handler", "a end handler",
+			"a end exec",
+		}, steps)
+		assert.Equal(t, "b", handledCtx.Value(ctxKeyTest{}))
+	})
+
+	t.Run("step errors are reported to hooks", func(t *testing.T) {
+		steps = nil
+		err := newCLI(func(context.Context, []string, []string) error {
+			return errors.New("boum")
+		}).Exec(context.Background(), []string{"sub"}, ExecWithIO(IO{Out: new(nopWriter), Err: new(nopWriter)}))
+		require.EqualError(t, err, "boum")
+		assert.Contains(t, steps, "b end handler: boum")
+		assert.Contains(t, steps, "a end exec: boum")
+	})
+}
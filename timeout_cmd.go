@@ -0,0 +1,121 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ctxKeyTimeout     ctxKey = "timeout"
+	annotationTimeout        = "clix_timeout"
+)
+
+// TimeoutError is returned when a handler failed after its context deadline exceeded.
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("command timed out after %s: %v", e.Timeout, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// ExitCode implements ExitCoder.
+func (e *TimeoutError) ExitCode() int { return ExitCodeTimeout }
+
+// WithTimeout sets the default timeout of the command handler context, inherited by
+// subcommands which can set their own, a zero timeout disabling it. The first decorated
+// command of the tree gets a persistent timeout flag, overriding the defaults.
+func WithTimeout(cbf CommandBuilderFunc, defaultTimeout time.Duration) CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		timeout, hasFlag := ctx.Value(ctxKeyTimeout).(*timeoutValue)
+		if !hasFlag {
+			timeout = new(timeoutValue)
+			ctx = context.WithValue(ctx, ctxKeyTimeout, timeout)
+		}
+
+		cmd, ctx, err := cbf(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+
+		setAnnotation(cmd, annotationTimeout, defaultTimeout.String())
+		if !hasFlag {
+			cmd.PersistentFlags().Var(timeout,
+				"timeout",
+				"maximum duration of the command, overrides the command default timeout",
+			)
+		}
+
+		return cmd, ctx, nil
+	}
+}
+
+// timeoutValue is the value of the timeout flag, its identity
+// distinguishes it from other flags with the same name.
+type timeoutValue time.Duration
+
+func (v *timeoutValue) String() string { return time.Duration(*v).String() }
+
+func (v *timeoutValue) Set(raw string) error {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+	*v = timeoutValue(d)
+	return nil
+}
+
+func (v *timeoutValue) Type() string { return "duration" }
+
+// commandTimeout returns the timeout of the command, from the flag
+// if provided, or from the closest command default timeout. A local flag
+// of the command, shadowing the timeout flag, is ignored.
+func commandTimeout(ctx context.Context, c *cobra.Command) (time.Duration, error) {
+	timeout, enabled := ctx.Value(ctxKeyTimeout).(*timeoutValue)
+	if !enabled {
+		return 0, nil
+	}
+	if flag := c.Flags().Lookup("timeout"); flag != nil && flag.Value == pflag.Value(timeout) && flag.Changed {
+		return time.Duration(*timeout), nil
+	}
+
+	for cmd := c; cmd != nil; cmd = cmd.Parent() {
+		if raw, hasTimeout := cmd.Annotations[annotationTimeout]; hasTimeout {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return 0, fmt.Errorf("unable to parse default timeout: %w", err)
+			}
+			return d, nil
+		}
+	}
+	return 0, nil
+}
+
+// handleWithTimeout calls the handle function with a context bounded by the command timeout.
+func handleWithTimeout(ctx context.Context, c *cobra.Command, handle func(ctx context.Context) error) error {
+	timeout, err := commandTimeout(ctx, c)
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return handle(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = handle(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package clix
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithTimeout(t *testing.T) {
+	newCLI := func(handle HandlerFunc) *CLI {
+		return Command(WithTimeout(testCommand("app", nil), time.Hour)).
+			SubCommand(testCommand("inherit", handle)).
+			SubCommand(WithTimeout(testCommand("short", handle), 10*time.Millisecond)).
+			SubCommand(WithTimeout(testCommand("unbounded", handle), 0)).
+			SubCommand(testCommand("shadowing", handle, func(cmd *cobra.Command) error {
+				cmd.Flags().String("timeout", "", "local flag with the same name")
+				return nil
+			}))
+	}
+	silent := ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard})
+
+	deadlineOf := func(t *testing.T, args ...string) (time.Duration, bool) {
+		var (
+			remaining   time.Duration
+			hasDeadline bool
+		)
+		require.NoError(t, newCLI(func(ctx context.Context, _, _ []string) error {
+			var deadline time.Time
+			deadline, hasDeadline = ctx.Deadline()
+			remaining = time.Until(deadline)
+			return nil
+		}).Exec(context.Background(), args, silent))
+		return remaining, hasDeadline
+	}
+
+	t.Run("default timeout is inherited", func(t *testing.T) {
+		remaining, hasDeadline := deadlineOf(t, "inherit")
+		assert.True(t, hasDeadline)
+		assert.InDelta(t, time.Hour, remaining, float64(time.Minute))
+	})
+
+	t.Run("default timeout is disabled", func(t *testing.T) {
+		_, hasDeadline := deadlineOf(t, "unbounded")
+		assert.False(t, hasDeadline)
+	})
+
+	t.Run("flag overrides default timeout", func(t *testing.T) {
+		remaining, hasDeadline := deadlineOf(t, "short", "--timeout", "2h")
+		assert.True(t, hasDeadline)
+		assert.InDelta(t, 2*time.Hour, remaining, float64(time.Minute))
+
+		_, hasDeadline = deadlineOf(t, "inherit", "--timeout", "0")
+		assert.False(t, hasDeadline)
+	})
+
+	t.Run("local flag with the same name is ignored", func(t *testing.T) {
+		remaining, hasDeadline := deadlineOf(t, "shadowing", "--timeout", "0")
+		assert.True(t, hasDeadline)
+		assert.InDelta(t, time.Hour, remaining, float64(time.Minute))
+	})
+
+	t.Run("timeout is reported", func(t *testing.T) {
+		err := newCLI(func(ctx context.Context, _, _ []string) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).Exec(context.Background(), []string{"short"}, silent)
+
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, ExitCodeTimeout, ExitCode(err))
+	})
+
+	t.Run("success after deadline is not a timeout", func(t *testing.T) {
+		err := newCLI(func(ctx context.Context, _, _ []string) error {
+			<-ctx.Done()
+			return nil
+		}).Exec(context.Background(), []string{"short"}, silent)
+		assert.NoError(t, err)
+	})
+
+	t.Run("cancellation is not a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := newCLI(func(ctx context.Context, _, _ []string) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}).Exec(ctx, []string{"inherit"}, silent)
+
+		var timeoutErr *TimeoutError
+		assert.False(t, errors.As(err, &timeoutErr))
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("provided command failed to be built", func(t *testing.T) {
+		_, _, err := WithTimeout(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return nil, nil, errors.New("boum")
+		}, time.Second)(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func Test_handleWithTimeout(t *testing.T) {
+	t.Run("without timeout in context", func(t *testing.T) {
+		err := handleWithTimeout(context.Background(), &cobra.Command{}, func(ctx context.Context) error {
+			_, hasDeadline := ctx.Deadline()
+			assert.False(t, hasDeadline)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid default timeout", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKeyTimeout, new(timeoutValue))
+		cmd := &cobra.Command{Annotations: map[string]string{annotationTimeout: "boum"}}
+		err := handleWithTimeout(ctx, cmd, func(context.Context) error { return nil })
+		assert.Error(t, err)
+	})
+}
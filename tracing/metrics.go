@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/krostar/clix"
+)
+
+// metrics records the executions of the command.
+type metrics struct {
+	executions metric.Int64Counter
+	duration   metric.Float64Histogram
+}
+
+func newMetrics(meter metric.Meter) (*metrics, error) {
+	executions, err := meter.Int64Counter("cli.executions",
+		metric.WithDescription("number of command executions"),
+		metric.WithUnit("{execution}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create executions counter: %w", err)
+	}
+
+	duration, err := meter.Float64Histogram("cli.duration",
+		metric.WithDescription("duration of command executions"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create duration histogram: %w", err)
+	}
+
+	return &metrics{executions: executions, duration: duration}, nil
+}
+
+func (m *metrics) record(ctx context.Context, command string, duration time.Duration, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	attributes := metric.WithAttributes(
+		attribute.String("cli.command", command),
+		attribute.Int("cli.exit_code", clix.ExitCode(err)),
+	)
+	m.executions.Add(ctx, 1, attributes)
+	m.duration.Record(ctx, duration.Seconds(), attributes)
+}
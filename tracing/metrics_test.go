@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/krostar/clix"
+)
+
+type recordingMeterProvider struct {
+	noop.MeterProvider
+	meter *recordingMeter
+}
+
+func (p recordingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter { return p.meter }
+
+func (p recordingMeterProvider) Shutdown(context.Context) error {
+	p.meter.shutdowns++
+	return nil
+}
+
+type recordingMeter struct {
+	noop.Meter
+	executions []attribute.Set
+	durations  []float64
+	shutdowns  int
+	err        error
+}
+
+func (m *recordingMeter) Int64Counter(string, ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return recordingCounter{meter: m}, m.err
+}
+
+func (m *recordingMeter) Float64Histogram(string, ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return recordingHistogram{meter: m}, nil
+}
+
+type recordingCounter struct {
+	noop.Int64Counter
+	meter *recordingMeter
+}
+
+func (c recordingCounter) Add(_ context.Context, _ int64, opts ...metric.AddOption) {
+	c.meter.executions = append(c.meter.executions, metric.NewAddConfig(opts).Attributes())
+}
+
+type recordingHistogram struct {
+	noop.Float64Histogram
+	meter *recordingMeter
+}
+
+func (h recordingHistogram) Record(_ context.Context, value float64, _ ...metric.RecordOption) {
+	h.meter.durations = append(h.meter.durations, value)
+}
+
+func Test_WithMeterProvider(t *testing.T) {
+	t.Run("executions are recorded", func(t *testing.T) {
+		meter := new(recordingMeter)
+		provider := recordingMeterProvider{meter: meter}
+
+		require.NoError(t, newTestCLI(func(context.Context, []string, []string) error { return nil },
+			WithMeterProvider(provider),
+		).Exec(context.Background(), []string{"deploy"}))
+
+		err := newTestCLI(func(context.Context, []string, []string) error { return errors.New("boum") },
+			WithMeterProvider(provider),
+		).Exec(context.Background(), []string{"deploy"}, clix.ExecWithIO(clix.IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)}))
+		require.Error(t, err)
+
+		require.Len(t, meter.executions, 2)
+		assert.Len(t, meter.durations, 2)
+		assert.Equal(t, 2, meter.shutdowns)
+		assert.Equal(t, attribute.NewSet(
+			attribute.String("cli.command", "app deploy"),
+			attribute.Int("cli.exit_code", clix.ExitCodeSuccess),
+		), meter.executions[0])
+		assert.Equal(t, attribute.NewSet(
+			attribute.String("cli.command", "app deploy"),
+			attribute.Int("cli.exit_code", clix.ExitCodeFailure),
+		), meter.executions[1])
+	})
+
+	t.Run("instruments can't be created", func(t *testing.T) {
+		meter := &recordingMeter{err: errors.New("boum")}
+		err := newTestCLI(func(context.Context, []string, []string) error { return nil },
+			WithMeterProvider(recordingMeterProvider{meter: meter}),
+		).Exec(context.Background(), []string{"deploy"})
+		assert.Error(t, err)
+	})
+}
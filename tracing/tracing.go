@@ -0,0 +1,168 @@
+// Package tracing provides an OpenTelemetry tracing of clix command executions,
+// and optionally records executions metrics with a provided meter provider.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/krostar/clix"
+)
+
+// Names of the built-in exporters.
+const (
+	ExporterNone    = "none"
+	ExporterConsole = "console"
+)
+
+const instrumentationName = "github.com/krostar/clix/tracing"
+
+// tracing traces a single execution: the root span covers the command
+// execution, and execution steps, like the handler, are its children.
+type tracing struct {
+	o        *options
+	exporter string
+	metrics  *metrics
+
+	start    time.Time
+	command  string
+	parent   context.Context
+	provider *sdktrace.TracerProvider
+	rootCtx  context.Context
+	root     trace.Span
+}
+
+// WithTracing traces the execution of the command and its subcommands. The exporter
+// is selected by a persistent flag, also bound to an environment variable, and traces
+// are not exported by default. The span of the handler is available in its context.
+// The root span is a child of the span of the context provided to Exec, if any.
+// Executions metrics are recorded when a meter provider is set with WithMeterProvider.
+// It is meant to decorate the root command, to trace the whole execution.
+func WithTracing(cbf clix.CommandBuilderFunc, opts ...Option) clix.CommandBuilderFunc {
+	return func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		o := defaultOptions()
+		for _, opt := range opts {
+			opt(o)
+		}
+
+		t := &tracing{o: o, exporter: o.defaultExporter}
+		if o.meterProvider != nil {
+			m, err := newMetrics(o.meterProvider.Meter(instrumentationName))
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to create metrics: %w", err)
+			}
+			t.metrics = m
+		}
+
+		cmd, ctx, err := clix.WithStepHook(cbf, t.hook)(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build command: %w", err)
+		}
+		t.command = cmd.CommandPath()
+
+		if app, hasApp := clix.AppFromContext(ctx); hasApp && app.Version != "" {
+			o.attributes = append(o.attributes, attribute.String("service.version", app.Version))
+		}
+
+		cmd.PersistentFlags().StringVar(&t.exporter,
+			"trace-exporter", t.exporter,
+			"where to export execution traces to, one of "+o.exporterNames(),
+		)
+		if o.env != "" {
+			if err := clix.BindFlagEnv(cmd.PersistentFlags(), "trace-exporter", o.env); err != nil {
+				return nil, nil, fmt.Errorf("unable to bind trace exporter flag: %w", err)
+			}
+		}
+		// the root span has to be started before the pre runs of decorators applied before, like the logger one
+		clix.PrependPersistentPreRunE(cmd, t.preRunInit)
+
+		return cmd, ctx, nil
+	}
+}
+
+func (t *tracing) preRunInit(cmd *cobra.Command, _ []string) error {
+	t.command = cmd.CommandPath()
+
+	exporter, err := t.o.newExporter(t.exporter, cmd.ErrOrStderr())
+	if err != nil || exporter == nil {
+		return err
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(append([]attribute.KeyValue{
+			attribute.String("service.name", cmd.Root().Name()),
+		}, t.o.attributes...)...)),
+	)
+
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+	parent := t.parent
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	attributes := []attribute.KeyValue{attribute.String("cli.command", cmd.CommandPath())}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		attributes = append(attributes, attribute.String("cli.flag."+flag.Name, clix.MaskFlagValue(flag)))
+	})
+
+	t.rootCtx, t.root = t.provider.Tracer(instrumentationName).Start(parent, cmd.CommandPath(),
+		trace.WithTimestamp(t.start),
+		trace.WithAttributes(attributes...),
+	)
+	return nil
+}
+
+func (t *tracing) hook(ctx context.Context, step string) (context.Context, func(error)) {
+	if step == clix.StepExec {
+		t.start, t.parent = time.Now(), ctx
+		return ctx, t.end
+	}
+
+	if t.root == nil {
+		return ctx, func(error) {}
+	}
+
+	_, span := t.provider.Tracer(instrumentationName).Start(t.rootCtx, step)
+	return trace.ContextWithSpan(ctx, span), func(err error) {
+		endSpan(span, err)
+	}
+}
+
+// end ends the root span, records the execution metrics,
+// and shuts the providers down, flushing their exporters.
+func (t *tracing) end(err error) {
+	if t.metrics != nil {
+		t.metrics.record(t.parent, t.command, time.Since(t.start), err)
+		if provider, canShutdown := t.o.meterProvider.(interface{ Shutdown(context.Context) error }); canShutdown {
+			provider.Shutdown(context.Background()) // nolint: errcheck, gosec
+		}
+	}
+
+	if t.root == nil {
+		return
+	}
+
+	endSpan(t.root, err)
+	t.provider.Shutdown(context.Background()) // nolint: errcheck, gosec
+	t.root, t.rootCtx, t.provider = nil, nil, nil
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type options struct {
+	defaultExporter string
+	env             string
+	exporters       map[string]sdktrace.SpanExporter
+	attributes      []attribute.KeyValue
+	meterProvider   metric.MeterProvider
+}
+
+func defaultOptions() *options {
+	return &options{
+		defaultExporter: ExporterNone,
+		env:             "CLIX_TRACE_EXPORTER",
+		exporters:       make(map[string]sdktrace.SpanExporter),
+	}
+}
+
+func (o *options) exporterNames() string {
+	names := []string{ExporterNone, ExporterConsole}
+	for name := range o.exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names[2:])
+	return strings.Join(names, "|")
+}
+
+// newExporter returns the exporter of the provided name,
+// or nil if traces should not be exported.
+func (o *options) newExporter(name string, console io.Writer) (sdktrace.SpanExporter, error) {
+	if exporter, exists := o.exporters[name]; exists {
+		return exporter, nil
+	}
+
+	switch name {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterConsole:
+		return stdouttrace.New(stdouttrace.WithWriter(console))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", name)
+	}
+}
+
+// Option defines the signature of an option applier.
+type Option func(o *options)
+
+// WithExporter registers an exporter, selectable by its name. The exporter is
+// shut down once the command executed. Use an in-memory exporter, like the one of
+// the otel tracetest package, to inspect traces.
+func WithExporter(name string, exporter sdktrace.SpanExporter) Option {
+	return func(o *options) { o.exporters[name] = exporter }
+}
+
+// WithDefaultExporter sets the name of the exporter used when none is selected.
+func WithDefaultExporter(name string) Option {
+	return func(o *options) { o.defaultExporter = name }
+}
+
+// WithEnv sets the environment variable the exporter can be selected with,
+// CLIX_TRACE_EXPORTER by default. An empty name disables the binding. The standard
+// OTEL_TRACES_EXPORTER is not used by default, as its values, like otlp, don't match
+// the names of the exporters.
+func WithEnv(env string) Option {
+	return func(o *options) { o.env = env }
+}
+
+// WithAttributes adds attributes to the resource every span belongs to.
+func WithAttributes(attributes ...attribute.KeyValue) Option {
+	return func(o *options) { o.attributes = append(o.attributes, attributes...) }
+}
+
+// WithMeterProvider records executions metrics with the provided meter provider:
+// the number of executions, and their duration, by command and exit code. Like the
+// tracer provider, the meter provider is shut down once the command executed,
+// if it can be, like the one of the otel metric SDK.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(o *options) { o.meterProvider = provider }
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_options_newExporter(t *testing.T) {
+	memory := tracetest.NewInMemoryExporter()
+	o := defaultOptions()
+	WithExporter("memory", memory)(o)
+
+	exporter, err := o.newExporter(ExporterNone, new(bytes.Buffer))
+	require.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	exporter, err = o.newExporter(ExporterConsole, new(bytes.Buffer))
+	require.NoError(t, err)
+	assert.NotNil(t, exporter)
+
+	exporter, err = o.newExporter("memory", new(bytes.Buffer))
+	require.NoError(t, err)
+	assert.Equal(t, memory, exporter)
+
+	_, err = o.newExporter("nope", new(bytes.Buffer))
+	assert.Error(t, err)
+
+	assert.Equal(t, "none|console|memory", o.exporterNames())
+}
+
+func Test_Options(t *testing.T) {
+	o := defaultOptions()
+	assert.Equal(t, ExporterNone, o.defaultExporter)
+	assert.Equal(t, "CLIX_TRACE_EXPORTER", o.env)
+	assert.Nil(t, o.meterProvider)
+
+	WithDefaultExporter(ExporterConsole)(o)
+	WithEnv("")(o)
+	WithAttributes(attribute.String("env", "prod"))(o)
+	WithMeterProvider(noop.NewMeterProvider())(o)
+
+	assert.Equal(t, ExporterConsole, o.defaultExporter)
+	assert.Empty(t, o.env)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("env", "prod")}, o.attributes)
+	assert.Equal(t, noop.NewMeterProvider(), o.meterProvider)
+}
@@ -0,0 +1,177 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krostar/logger"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/krostar/clix"
+)
+
+func newTestCLI(handle clix.HandlerFunc, opts ...Option) *clix.CLI {
+	return clix.Command(WithTracing(clix.WithLogger(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{Use: "app"}, ctx, nil
+	}, clix.LoggerWithCreateFunc(func(logger.Config) (logger.Logger, error) {
+		return logger.NewInMemory(logger.LevelInfo), nil
+	})), opts...)).SubCommand(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		var (
+			token  clix.Secret
+			region string
+		)
+		cmd := &cobra.Command{
+			Use:  "deploy",
+			RunE: clix.ExecHandler(ctx, func(func()) (clix.Handler, error) { return handle, nil }),
+		}
+		clix.SecretVar(cmd.Flags(), &token, "token", "", "")
+		cmd.Flags().StringVar(&region, "region", "", "")
+		return cmd, ctx, nil
+	})
+}
+
+// memoryExporter keeps the exported spans once shut down,
+// unlike the in-memory exporter of the otel tracetest package.
+type memoryExporter struct {
+	*tracetest.InMemoryExporter
+	shutdowns int
+}
+
+func newMemoryExporter() *memoryExporter {
+	return &memoryExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error {
+	e.shutdowns++
+	return nil
+}
+
+func spanNamed(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", "no span named %q", name)
+	return tracetest.SpanStub{}
+}
+
+func Test_WithTracing(t *testing.T) {
+	t.Run("executions are traced", func(t *testing.T) {
+		exporter := newMemoryExporter()
+
+		var handlerSpan trace.SpanContext
+		require.NoError(t, newTestCLI(func(ctx context.Context, _, _ []string) error {
+			handlerSpan = trace.SpanContextFromContext(ctx)
+			return nil
+		}, WithExporter("memory", exporter)).Exec(context.Background(), []string{
+			"deploy", "--trace-exporter", "memory", "--region", "eu", "--token", "s3cr3t",
+		}))
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, 1, exporter.shutdowns)
+		require.Len(t, spans, 3)
+
+		root := spanNamed(t, spans, "app deploy")
+		assert.False(t, root.Parent.IsValid())
+		assert.Contains(t, root.Attributes, attribute.String("cli.flag.region", "eu"))
+		assert.Contains(t, root.Attributes, attribute.String("cli.flag.token", "****"))
+		assert.Contains(t, root.Resource.Attributes(), attribute.String("service.name", "app"))
+
+		for _, step := range []string{clix.StepLoggerInit, clix.StepHandler} {
+			span := spanNamed(t, spans, step)
+			assert.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID(), step)
+			assert.Equal(t, root.SpanContext.TraceID(), span.SpanContext.TraceID(), step)
+			assert.False(t, span.StartTime.Before(root.StartTime), step)
+		}
+		assert.Equal(t, spanNamed(t, spans, clix.StepHandler).SpanContext.SpanID(), handlerSpan.SpanID())
+	})
+
+	t.Run("errors are recorded", func(t *testing.T) {
+		exporter := newMemoryExporter()
+
+		err := newTestCLI(func(context.Context, []string, []string) error {
+			return errors.New("boum")
+		}, WithExporter("memory", exporter), WithDefaultExporter("memory")).Exec(
+			context.Background(), []string{"deploy"}, clix.ExecWithIO(clix.IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)}),
+		)
+		require.EqualError(t, err, "boum")
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 3)
+		assert.Equal(t, codes.Error, spanNamed(t, spans, "app deploy").Status.Code)
+		assert.Equal(t, "boum", spanNamed(t, spans, clix.StepHandler).Status.Description)
+	})
+
+	t.Run("exporter can be selected through env", func(t *testing.T) {
+		exporter := newMemoryExporter()
+		t.Setenv("APP_TRACES", "memory")
+
+		require.NoError(t, newTestCLI(func(context.Context, []string, []string) error { return nil },
+			WithExporter("memory", exporter), WithEnv("APP_TRACES"),
+		).Exec(context.Background(), []string{"deploy"}))
+		assert.Len(t, exporter.GetSpans(), 3)
+	})
+
+	t.Run("standard otel exporter variable is ignored", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+		require.NoError(t, newTestCLI(func(context.Context, []string, []string) error { return nil }).Exec(
+			context.Background(), []string{"deploy"},
+		))
+	})
+
+	t.Run("root span is a child of the exec context span", func(t *testing.T) {
+		exporter := newMemoryExporter()
+		parent := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{2},
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		})
+
+		require.NoError(t, newTestCLI(func(context.Context, []string, []string) error { return nil },
+			WithExporter("memory", exporter), WithDefaultExporter("memory"),
+		).Exec(trace.ContextWithRemoteSpanContext(context.Background(), parent), []string{"deploy"}))
+
+		root := spanNamed(t, exporter.GetSpans(), "app deploy")
+		assert.Equal(t, parent.SpanID(), root.Parent.SpanID())
+		assert.Equal(t, parent.TraceID(), root.SpanContext.TraceID())
+	})
+
+	t.Run("traces are printed by the console exporter", func(t *testing.T) {
+		var stderr bytes.Buffer
+
+		require.NoError(t, newTestCLI(func(context.Context, []string, []string) error { return nil }).Exec(
+			context.Background(), []string{"deploy", "--trace-exporter", "console"},
+			clix.ExecWithIO(clix.IO{Out: new(bytes.Buffer), Err: &stderr}),
+		))
+		assert.Contains(t, stderr.String(), `"Name":"app deploy"`)
+		assert.Contains(t, stderr.String(), `"Name":"handler"`)
+	})
+
+	t.Run("nothing is traced by default", func(t *testing.T) {
+		var handlerSpan trace.SpanContext
+		require.NoError(t, newTestCLI(func(ctx context.Context, _, _ []string) error {
+			handlerSpan = trace.SpanContextFromContext(ctx)
+			return nil
+		}).Exec(context.Background(), []string{"deploy"}))
+		assert.False(t, handlerSpan.IsValid())
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		err := newTestCLI(func(context.Context, []string, []string) error { return nil }).Exec(
+			context.Background(), []string{"deploy", "--trace-exporter", "jaeger"},
+			clix.ExecWithIO(clix.IO{Out: new(bytes.Buffer), Err: new(bytes.Buffer)}),
+		)
+		assert.EqualError(t, err, `unknown trace exporter "jaeger"`)
+	})
+}
@@ -0,0 +1,132 @@
+package clix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// readBuildInfo is overridden in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// VersionInfo describes the version of the running application.
+type VersionInfo struct {
+	App          `yaml:",inline"`
+	Dirty        bool                `json:"dirty" yaml:"dirty"`
+	GoVersion    string              `json:"go_version,omitempty" yaml:"go_version,omitempty"`
+	Dependencies []VersionDependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// VersionDependency describes the version of a module the application depends on.
+type VersionDependency struct {
+	Path    string `json:"path" yaml:"path"`
+	Version string `json:"version" yaml:"version"`
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+}
+
+// NewVersionInfo creates the version of the running application from the build
+// information embedded in the binary. Values set in the provided application,
+// like the ones injected with -ldflags, are used when the build information lacks them.
+func NewVersionInfo(app App) VersionInfo {
+	info := VersionInfo{App: app, GoVersion: runtime.Version()}
+
+	bi, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+
+	if v := bi.Main.Version; v != "" && v != "(devel)" {
+		info.Version = v
+	}
+	if bi.GoVersion != "" {
+		info.GoVersion = bi.GoVersion
+	}
+
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.BuildDate = setting.Value
+		case "vcs.modified":
+			info.Dirty = setting.Value == "true"
+		}
+	}
+
+	for _, dep := range bi.Deps {
+		dependency := VersionDependency{Path: dep.Path, Version: dep.Version}
+		if dep.Replace != nil {
+			dependency.Replace = dep.Replace.Path
+			if dep.Replace.Version != "" {
+				dependency.Replace += "@" + dep.Replace.Version
+			}
+		}
+		info.Dependencies = append(info.Dependencies, dependency)
+	}
+
+	return info
+}
+
+// VersionCommand builds a version command printing the version of the running
+// application. It is automatically added by WithApp, whose application metadata are
+// used as fallback values. The version is rendered with the output format set by
+// WithOutput, the default table format being printed as a text listing.
+func VersionCommand(ctx context.Context) (*cobra.Command, context.Context, error) {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the application version",
+		Args:  cobra.NoArgs,
+		RunE: ExecHandler(ctx, func(func()) (Handler, error) {
+			return HandlerFunc(func(ctx context.Context, _, _ []string) error {
+				app, _ := AppFromContext(ctx)
+				info := NewVersionInfo(app)
+
+				if output := OutputFromContext(ctx); output != nil && output.Format.Name != OutputFormatTable {
+					return output.Render(info)
+				}
+				return info.writeText(IOFromContext(ctx).Out)
+			}), nil
+		}),
+	}
+
+	return cmd, ctx, nil
+}
+
+func (info VersionInfo) writeText(w io.Writer) error {
+	commit := info.Commit
+	if commit != "" && info.Dirty {
+		commit += " (dirty)"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, field := range []struct{ name, value string }{
+		{name: "Name", value: info.Name},
+		{name: "Version", value: info.Version},
+		{name: "Commit", value: commit},
+		{name: "Build date", value: info.BuildDate},
+		{name: "Go version", value: info.GoVersion},
+		{name: "Homepage", value: info.Homepage},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", field.name, field.value) // nolint: errcheck, gosec
+		}
+	}
+
+	if len(info.Dependencies) > 0 {
+		fmt.Fprintln(tw, "Dependencies:") // nolint: errcheck, gosec
+		for _, dep := range info.Dependencies {
+			version := dep.Version
+			if dep.Replace != "" {
+				version += " => " + dep.Replace
+			}
+			fmt.Fprintf(tw, "  %s\t%s\n", dep.Path, version) // nolint: errcheck, gosec
+		}
+	}
+
+	return tw.Flush()
+}
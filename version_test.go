@@ -0,0 +1,155 @@
+package clix
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withBuildInfo(t *testing.T, bi *debug.BuildInfo) {
+	original := readBuildInfo
+	t.Cleanup(func() { readBuildInfo = original })
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return bi, bi != nil }
+}
+
+func Test_NewVersionInfo(t *testing.T) {
+	app := App{Name: "app", Version: "1.2.3", Commit: "abcdef", BuildDate: "2020-01-02"}
+
+	t.Run("without build info", func(t *testing.T) {
+		withBuildInfo(t, nil)
+		assert.Equal(t, VersionInfo{App: app, GoVersion: runtime.Version()}, NewVersionInfo(app))
+	})
+
+	t.Run("build info lacks values", func(t *testing.T) {
+		withBuildInfo(t, &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}})
+		assert.Equal(t, VersionInfo{App: app, GoVersion: runtime.Version()}, NewVersionInfo(app))
+	})
+
+	t.Run("build info values are preferred", func(t *testing.T) {
+		withBuildInfo(t, &debug.BuildInfo{
+			GoVersion: "go1.21.0",
+			Main:      debug.Module{Version: "v2.0.0"},
+			Deps: []*debug.Module{
+				{Path: "github.com/a/a", Version: "v1.0.0"},
+				{Path: "github.com/b/b", Version: "v1.0.0", Replace: &debug.Module{Path: "github.com/c/b", Version: "v1.1.0"}},
+				{Path: "github.com/d/d", Version: "v1.0.0", Replace: &debug.Module{Path: "../d"}},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "123456"},
+				{Key: "vcs.time", Value: "2021-02-03T04:05:06Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		})
+
+		assert.Equal(t, VersionInfo{
+			App:       App{Name: "app", Version: "v2.0.0", Commit: "123456", BuildDate: "2021-02-03T04:05:06Z"},
+			Dirty:     true,
+			GoVersion: "go1.21.0",
+			Dependencies: []VersionDependency{
+				{Path: "github.com/a/a", Version: "v1.0.0"},
+				{Path: "github.com/b/b", Version: "v1.0.0", Replace: "github.com/c/b@v1.1.0"},
+				{Path: "github.com/d/d", Version: "v1.0.0", Replace: "../d"},
+			},
+		}, NewVersionInfo(app))
+	})
+}
+
+func Test_VersionCommand(t *testing.T) {
+	withBuildInfo(t, &debug.BuildInfo{
+		GoVersion: "go1.21.0",
+		Main:      debug.Module{Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/a/a", Version: "v1.0.0"},
+			{Path: "github.com/bb/bb", Version: "v1.0.0", Replace: &debug.Module{Path: "../bb"}},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "123456"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	})
+
+	cli := Command(WithApp(WithOutput(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+		return &cobra.Command{}, ctx, nil
+	}), AppWithName("app"), AppWithVersion("1.2.3")))
+
+	t.Run("as text", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, cli.Exec(context.Background(), []string{"version"}, ExecWithIO(IO{Out: &out})))
+		assert.Equal(t, `Name:        app
+Version:     1.2.3
+Commit:      123456 (dirty)
+Go version:  go1.21.0
+Dependencies:
+  github.com/a/a    v1.0.0
+  github.com/bb/bb  v1.0.0 => ../bb
+`, out.String())
+	})
+
+	t.Run("as json", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, cli.Exec(context.Background(), []string{"version", "--output", "json"}, ExecWithIO(IO{Out: &out})))
+		assert.JSONEq(t, `{
+			"name": "app",
+			"version": "1.2.3",
+			"commit": "123456",
+			"dirty": true,
+			"go_version": "go1.21.0",
+			"dependencies": [
+				{"path": "github.com/a/a", "version": "v1.0.0"},
+				{"path": "github.com/bb/bb", "version": "v1.0.0", "replace": "../bb"}
+			]
+		}`, out.String())
+	})
+
+	t.Run("as yaml", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, cli.Exec(context.Background(), []string{"version", "-o", "yaml"}, ExecWithIO(IO{Out: &out})))
+		assert.Equal(t, `name: app
+version: 1.2.3
+commit: "123456"
+dirty: true
+go_version: go1.21.0
+dependencies:
+- path: github.com/a/a
+  version: v1.0.0
+- path: github.com/bb/bb
+  version: v1.0.0
+  replace: ../bb
+`, out.String())
+	})
+
+	t.Run("as template", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, cli.Exec(context.Background(), []string{"version", "-o", "template={{.Version}}"}, ExecWithIO(IO{Out: &out})))
+		assert.Equal(t, "1.2.3", out.String())
+	})
+
+	t.Run("without output", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Command(WithApp(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{}, ctx, nil
+		}, AppWithName("app"))).Exec(context.Background(), []string{"version"}, ExecWithIO(IO{Out: &out})))
+		assert.Contains(t, out.String(), "Name:        app\n")
+	})
+
+	t.Run("without app", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Command(func(ctx context.Context) (*cobra.Command, context.Context, error) {
+			return &cobra.Command{}, ctx, nil
+		}).SubCommand(VersionCommand).Exec(context.Background(), []string{"version"}, ExecWithIO(IO{Out: &out})))
+		assert.Contains(t, out.String(), "Commit:      123456 (dirty)\n")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		err := cli.Exec(context.Background(), []string{"version", "-o", "xml"},
+			ExecWithIO(IO{Out: ioutil.Discard, Err: ioutil.Discard}))
+		assert.Error(t, err)
+	})
+}